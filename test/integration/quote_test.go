@@ -1,8 +1,12 @@
 package integration
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/golang-sql/civil"
 	"github.com/miles170/twstock-go/twstock"
 )
 
@@ -13,7 +17,7 @@ func TestQuote_DownloadTwse(t *testing.T) {
 		t.Fatalf("DownloadTwse returned error: %v", err)
 	}
 	_, err = client.Quote.DownloadTwse("3374", 2022, 12)
-	if err != twstock.ErrNoData {
+	if !errors.Is(err, twstock.ErrNoData) {
 		t.Fatalf("DownloadTwse error should be %v got %v", twstock.ErrNoData, err)
 	}
 }
@@ -29,7 +33,26 @@ func TestQuote_DownloadTpex(t *testing.T) {
 		t.Fatalf("DownloadTpex returned error: %v", err)
 	}
 	_, err = client.Quote.DownloadTpex("2330", 2022, 12)
-	if err != twstock.ErrNoData {
+	if !errors.Is(err, twstock.ErrNoData) {
 		t.Fatalf("DownloadTpex error should be %v got %v", twstock.ErrNoData, err)
 	}
 }
+
+func TestQuote_DownloadDaily(t *testing.T) {
+	client := twstock.NewClient()
+	date := civil.Date{Year: 2022, Month: time.December, Day: 30}
+	quotes, err := client.Quote.DownloadTwseDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTwseDaily returned error: %v", err)
+	}
+	if _, ok := quotes["2330"]; !ok {
+		t.Errorf("DownloadTwseDaily returned no quote for %s", "2330")
+	}
+	quotes, err = client.Quote.DownloadTpexDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTpexDaily returned error: %v", err)
+	}
+	if _, ok := quotes["3374"]; !ok {
+		t.Errorf("DownloadTpexDaily returned no quote for %s", "3374")
+	}
+}
@@ -0,0 +1,23 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/miles170/twstock-go/twstock"
+)
+
+func TestSBL_Download(t *testing.T) {
+	client := twstock.NewClient()
+	date := civil.Date{Year: 2022, Month: time.December, Day: 30}
+	_, err := client.SBL.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTwse returned error: %v", err)
+	}
+	_, err = client.SBL.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTpex returned error: %v", err)
+	}
+}
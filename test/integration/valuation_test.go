@@ -0,0 +1,35 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/miles170/twstock-go/twstock"
+)
+
+func TestValuation_Download(t *testing.T) {
+	client := twstock.NewClient()
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, 12)
+	if err != nil {
+		t.Fatalf("DownloadTwse returned error: %v", err)
+	}
+	_, err = client.Valuation.DownloadTpex(context.Background(), "3374", 2022, 12)
+	if err != nil {
+		t.Fatalf("DownloadTpex returned error: %v", err)
+	}
+}
+
+func TestValuation_DownloadDaily(t *testing.T) {
+	client := twstock.NewClient()
+	date := civil.Date{Year: 2022, Month: time.December, Day: 30}
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTwseDaily returned error: %v", err)
+	}
+	_, err = client.Valuation.DownloadTpexDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DownloadTpexDaily returned error: %v", err)
+	}
+}
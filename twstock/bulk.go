@@ -0,0 +1,88 @@
+package twstock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang-sql/civil"
+)
+
+// 批次下載預設同時進行的數量，實際的請求頻率仍受各網站的頻率限制
+const defaultBulkWorkers = 4
+
+// BulkOptions 設定批次下載
+type BulkOptions struct {
+	Workers  int                // 同時下載的數量，小於 1 時使用預設值
+	Progress func(BulkProgress) // 每完成一檔個股時呼叫，不會同時被呼叫
+}
+
+// BulkProgress 批次下載的進度
+type BulkProgress struct {
+	Code  string // 剛完成的個股代號
+	Err   error  // 該個股的錯誤
+	Done  int    // 已完成的數量
+	Total int    // 總數量
+}
+
+// BulkResult 批次下載單一個股的結果
+type BulkResult struct {
+	Quotes []Quote // 盤後日成交資訊
+	Err    error   // 下載失敗的原因
+}
+
+// 同時從台灣證卷交易所或證券櫃檯買賣中心下載多檔個股 from 到 to（包含）之間的盤後日成交資訊，
+// 單一個股下載失敗不會中斷其他個股，錯誤會記錄在對應的 BulkResult
+func (s *QuoteService) DownloadBulk(ctx context.Context, codes []string, from, to civil.Date, opts *BulkOptions) map[string]BulkResult {
+	workers := defaultBulkWorkers
+	var progress func(BulkProgress)
+	if opts != nil {
+		if opts.Workers > 0 {
+			workers = opts.Workers
+		}
+		progress = opts.Progress
+	}
+
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !seen[code] {
+			seen[code] = true
+			unique = append(unique, code)
+		}
+	}
+
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		for _, code := range unique {
+			jobs <- code
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]BulkResult, len(unique))
+	for i := 0; i < workers && i < len(unique); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for code := range jobs {
+				var result BulkResult
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Quotes, result.Err = s.DownloadRange(ctx, code, from, to)
+				}
+
+				mu.Lock()
+				results[code] = result
+				if progress != nil {
+					progress(BulkProgress{Code: code, Err: result.Err, Done: len(results), Total: len(unique)})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,80 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+)
+
+func TestQuoteService_DownloadBulk(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("stockNo") == "3049" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{
+			"stat": "OK",
+			"fields": ["日期","成交股數","成交金額","開盤價","最高價","最低價","收盤價","漲跌價差","成交筆數","註記"],
+			"data": [["111/08/01","1","1","1.00","1.00","1.00","1.00","0.00","1",""]]
+		}`)
+	})
+
+	progress := []BulkProgress{}
+	from := civil.Date{Year: 2022, Month: time.August, Day: 1}
+	to := civil.Date{Year: 2022, Month: time.August, Day: 31}
+	results := client.Quote.DownloadBulk(context.Background(), []string{"2330", "3049", "BAD", "2330"}, from, to, &BulkOptions{
+		Workers: 2,
+		Progress: func(p BulkProgress) {
+			progress = append(progress, p)
+		},
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("Quote.DownloadBulk returned %d results, want 3", len(results))
+	}
+	if result := results["2330"]; result.Err != nil || len(result.Quotes) != 1 {
+		t.Errorf("Quote.DownloadBulk returned %+v for 2330, want 1 quote", result)
+	}
+	if result := results["3049"]; result.Err == nil {
+		t.Error("Quote.DownloadBulk returned nil error for 3049; expected error")
+	}
+	if result := results["BAD"]; result.Err == nil {
+		t.Error("Quote.DownloadBulk returned nil error for BAD; expected error")
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("Quote.DownloadBulk reported progress %d times, want 3", len(progress))
+	}
+	for i, p := range progress {
+		if p.Done != i+1 || p.Total != 3 {
+			t.Errorf("Quote.DownloadBulk reported progress %+v, want %d of 3", p, i+1)
+		}
+		if p.Err != results[p.Code].Err {
+			t.Errorf("Quote.DownloadBulk reported error %v for %s, want %v", p.Err, p.Code, results[p.Code].Err)
+		}
+	}
+}
+
+func TestQuoteService_DownloadBulkCanceled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	from := civil.Date{Year: 2022, Month: time.August, Day: 1}
+	results := client.Quote.DownloadBulk(ctx, []string{"2330", "3374"}, from, from, nil)
+	for _, code := range []string{"2330", "3374"} {
+		if !errors.Is(results[code].Err, context.Canceled) {
+			t.Errorf("Quote.DownloadBulk returned %v for %s, want %v", results[code].Err, code, context.Canceled)
+		}
+	}
+}
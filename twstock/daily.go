@@ -0,0 +1,180 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+)
+
+const (
+	// 上市每日收盤行情（全部）
+	twseDailyQuotesPath = "/rwd/zh/afterTrading/MI_INDEX"
+
+	// 上櫃股票每日收盤行情
+	tpexDailyQuotesPath = "/www/zh-tw/afterTrading/dailyQuotes"
+)
+
+type twseDailyOptions struct {
+	Response string `url:"response"`
+	Date     string `url:"date"`
+	Type     string `url:"type"`
+}
+
+type tpexDailyOptions struct {
+	Response string `url:"response"`
+	Date     string `url:"date"`
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心全市場每日收盤行情的最小查詢日期
+func (s *QuoteService) MinimumDailyDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所每日收盤行情最早到民國93年2月11日
+		return civil.Date{Year: 2004, Month: time.February, Day: 11}
+	}
+	// 證券櫃檯買賣中心每日收盤行情最早到民國96年4月23日
+	return civil.Date{Year: 2007, Month: time.April, Day: 23}
+}
+
+// isNoTrade reports whether a daily price is a placeholder such as -- or ----
+// for securities without any trade on that day.
+func isNoTrade(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && strings.Trim(s, "-") == ""
+}
+
+// parseDaily converts a row of daily quotes into the layout of parse, prices
+// without any trade are normalized to --.
+func (s *QuoteService) parseDaily(date civil.Date, volume, tradeValue, open, high, low, close, change, transactions string) (Quote, error) {
+	data := []string{
+		fmt.Sprintf("%d/%02d/%02d", date.Year-1911, date.Month, date.Day),
+		volume, tradeValue, open, high, low, close, change, transactions,
+	}
+	for i := 3; i <= 6; i++ {
+		if isNoTrade(data[i]) {
+			data[i] = "--"
+		}
+	}
+	return s.parse(data)
+}
+
+// 從台灣證卷交易所下載指定日期所有上市證券的盤後日成交資訊，沒有成交的證券 Suspended 為 true
+func (s *QuoteService) DownloadTwseDaily(ctx context.Context, date civil.Date) (map[string]Quote, error) {
+	if date.Before(s.MinimumDailyDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseDailyQuotesPath)
+	opts := twseDailyOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		Type:     "ALLBUT0999",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	for _, table := range resp.Tables {
+		fields := table.Fields
+		if len(fields) < 11 ||
+			fields[0] != "證券代號" ||
+			fields[2] != "成交股數" ||
+			fields[3] != "成交筆數" ||
+			fields[4] != "成交金額" ||
+			fields[5] != "開盤價" ||
+			fields[6] != "最高價" ||
+			fields[7] != "最低價" ||
+			fields[8] != "收盤價" ||
+			fields[9] != "漲跌(+/-)" ||
+			fields[10] != "漲跌價差" {
+			continue
+		}
+		quotes := map[string]Quote{}
+		for _, data := range table.Data {
+			if len(data) < 11 {
+				return nil, fmt.Errorf("failed parsing quote fields")
+			}
+			code := strings.TrimSpace(data[0])
+			// 漲跌價差不含正負號，需要與漲跌(+/-)欄位合併
+			quote, err := s.parseDaily(date, data[2], data[4], data[5], data[6], data[7], data[8], data[9]+data[10], data[3])
+			if err != nil && !errors.Is(err, errSuspendedTrading) {
+				return nil, fmt.Errorf("failed parsing quote %s: %w", code, err)
+			}
+			quotes[code] = quote
+		}
+		return quotes, nil
+	}
+	// stat 為 OK 時一定有個股行情表，找不到代表欄位已經變更
+	return nil, fmt.Errorf("failed parsing quote fields: quotes table not found")
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃證券的盤後日成交資訊，沒有成交的證券 Suspended 為 true
+func (s *QuoteService) DownloadTpexDaily(ctx context.Context, date civil.Date) (map[string]Quote, error) {
+	if date.Before(s.MinimumDailyDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexDailyQuotesPath)
+	opts := tpexDailyOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	date, err = tpexTableDate(date, table.Date)
+	if err != nil {
+		return nil, err
+	}
+	if table.TotalCount != len(table.Data) {
+		return nil, fmt.Errorf("failed parsing quote data length returned %d, want %d", table.TotalCount, len(table.Data))
+	}
+	fields := make([]string, len(table.Fields))
+	for i, v := range table.Fields {
+		fields[i] = strings.TrimSpace(v)
+	}
+	if len(fields) < 11 ||
+		fields[0] != "代號" ||
+		fields[2] != "收盤" ||
+		fields[3] != "漲跌" ||
+		fields[4] != "開盤" ||
+		fields[5] != "最高" ||
+		fields[6] != "最低" ||
+		fields[8] != "成交股數" ||
+		fields[9] != "成交金額(元)" ||
+		fields[10] != "成交筆數" {
+		return nil, fmt.Errorf("failed parsing quote fields: %s", strings.Join(fields, ","))
+	}
+	quotes := map[string]Quote{}
+	for _, row := range table.Data {
+		if len(row) < 11 {
+			return nil, fmt.Errorf("failed parsing quote fields")
+		}
+		data := tpexStrings(row)
+		code := strings.TrimSpace(data[0])
+		quote, err := s.parseDaily(date, data[8], data[9], data[4], data[5], data[6], data[2], data[3], data[10])
+		if err != nil && !errors.Is(err, errSuspendedTrading) {
+			return nil, fmt.Errorf("failed parsing quote %s: %w", code, err)
+		}
+		quotes[code] = quote
+	}
+	return quotes, nil
+}
@@ -0,0 +1,339 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestQuoteService_DownloadTwseDaily(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("type"), "ALLBUT0999"; got != want {
+			t.Errorf("Request type = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"tables": [
+				{
+					"title": "111年08月22日 價格指數(臺灣證券交易所)",
+					"fields": ["指數", "收盤指數", "漲跌(+/-)", "漲跌點數", "漲跌百分比(%)", "特殊處理註記"],
+					"data": [["發行量加權股價指數", "15,081.44", "<p style= color:green>-</p>", "327.34", "-2.12", ""]]
+				},
+				{
+					"title": "111年08月22日 每日收盤行情(全部(不含權證、牛熊證))",
+					"fields": ["證券代號", "證券名稱", "成交股數", "成交筆數", "成交金額", "開盤價", "最高價", "最低價", "收盤價", "漲跌(+/-)", "漲跌價差", "最後揭示買價", "最後揭示買量", "最後揭示賣價", "最後揭示賣量", "本益比"],
+					"data": [
+						["2330", "台積電", "20,813,175", "29,811", "10,668,565,145", "511.00", "514.00", "510.00", "510.00", "<p style= color:green>-</p>", "9.00", "510.00", "403", "511.00", "149", "14.23"],
+						["1101", "台泥", "8,217,310", "4,683", "377,012,380", "46.10", "46.10", "45.70", "45.85", "<p style= color:red>+</p>", "0.05", "45.85", "177", "45.90", "253", "15.86"],
+						["0050", "元大台灣50", "8,031,722", "7,863", "966,126,700", "121.15", "121.15", "119.90", "120.00", "<p> X</p>", "0.00", "120.00", "1", "120.05", "71", "0.00"],
+						["1258", "其祥-KY", "0", "0", "0", "--", "--", "--", "--", "<p> </p>", "0.00", "35.00", "1", "35.50", "2", "0.00"]
+					]
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	quotes, err := client.Quote.DownloadTwseDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Quote.DownloadTwseDaily returned error: %v", err)
+	}
+	want := map[string]Quote{
+		"2330": {
+			Date:         date,
+			Open:         decimal.NewFromInt(511),
+			High:         decimal.NewFromInt(514),
+			Low:          decimal.NewFromInt(510),
+			Close:        decimal.NewFromInt(510),
+			Volume:       20813175,
+			TradeValue:   decimal.NewFromInt(10668565145),
+			Change:       decimal.NewFromInt(-9),
+			Transactions: 29811,
+		},
+		"1101": {
+			Date:         date,
+			Open:         decimal.RequireFromString("46.1"),
+			High:         decimal.RequireFromString("46.1"),
+			Low:          decimal.RequireFromString("45.7"),
+			Close:        decimal.RequireFromString("45.85"),
+			Volume:       8217310,
+			TradeValue:   decimal.NewFromInt(377012380),
+			Change:       decimal.RequireFromString("0.05"),
+			Transactions: 4683,
+		},
+		"0050": {
+			Date:         date,
+			Open:         decimal.RequireFromString("121.15"),
+			High:         decimal.RequireFromString("121.15"),
+			Low:          decimal.RequireFromString("119.9"),
+			Close:        decimal.NewFromInt(120),
+			Volume:       8031722,
+			TradeValue:   decimal.NewFromInt(966126700),
+			Change:       decimal.Zero,
+			Transactions: 7863,
+		},
+		"1258": {
+			Date:      date,
+			Suspended: true,
+		},
+	}
+	if !cmp.Equal(quotes, want) {
+		t.Errorf("Quote.DownloadTwseDaily returned %+v, want %+v", quotes, want)
+	}
+}
+
+func TestQuoteService_DownloadTwseDailyErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Quote.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Quote.DownloadTwseDaily returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestQuoteService_DownloadTwseDailyNoQuotesTable(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["指數", "收盤指數"], "data": []}]}`)
+	})
+
+	_, err := client.Quote.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTwseDaily returned nil; expected error")
+	}
+	if errors.Is(err, ErrNoData) {
+		t.Errorf("Quote.DownloadTwseDaily returned %v, want fields error", err)
+	}
+	testErrorContains(t, err, "failed parsing quote fields: quotes table not found")
+}
+
+func TestQuoteService_DownloadTwseDailyBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["證券代號", "證券名稱", "成交股數", "成交筆數", "成交金額", "開盤價", "最高價", "最低價", "收盤價", "漲跌(+/-)", "漲跌價差"], "data": [["2330"]]}]}`)
+	})
+
+	_, err := client.Quote.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote fields")
+}
+
+func TestQuoteService_DownloadTwseDailyBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["證券代號", "證券名稱", "成交股數", "成交筆數", "成交金額", "開盤價", "最高價", "最低價", "收盤價", "漲跌(+/-)", "漲跌價差"], "data": [["2330", "台積電", "1", "1", "1", "BAD", "1", "1", "1", "", "0.00"]]}]}`)
+	})
+
+	_, err := client.Quote.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote 2330")
+}
+
+func TestQuoteService_DownloadTwseDailyInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Quote.DownloadTwseDaily(context.Background(), civil.Date{Year: 2004, Month: time.February, Day: 10})
+	if err == nil {
+		t.Fatal("Quote.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2004-02-10")
+}
+
+func TestQuoteService_DownloadTpexDaily(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"tables": [
+				{
+					"title": "上櫃股票行情",
+					"date": "20220822",
+					"fields": ["代號", "名稱", "收盤 ", "漲跌", "開盤 ", "最高 ", "最低", "均價 ", "成交股數  ", "成交金額(元)", "成交筆數 ", "最後買價", "最後買量(千股)", "最後賣價", "最後賣量(千股)", "發行股數 ", "次日參考價 ", "次日漲停價", "次日跌停價"],
+					"data": [
+						["3374", "精材", "128.50", "-1.00 ", "128.50", "133.50", "128.50", "130.44", "5,086,452", "663,499,104", "3,815", "128.00", "152", "128.50", "19", "271,680,000", "128.50", "141.00", "116.00"],
+						["3105", "穩懋", "212.50", "除息", "215.00", "216.00", "211.00", "213.21", 4567000, "973,730,000", "4,213", "212.00", "10", "212.50", "3", "424,000,000", "212.50", "233.50", "191.50"],
+						["6294", "智基", "----", " 0.00", "----", "----", "----", "----", "0", "0", "0", "40.00", "1", "41.00", "2", "30,000,000", "40.50", "44.55", "36.45"]
+					],
+					"totalCount": 3
+				}
+			],
+			"date": "20220822",
+			"stat": "ok"
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	quotes, err := client.Quote.DownloadTpexDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Quote.DownloadTpexDaily returned error: %v", err)
+	}
+	want := map[string]Quote{
+		"3374": {
+			Date:         date,
+			Open:         decimal.RequireFromString("128.5"),
+			High:         decimal.RequireFromString("133.5"),
+			Low:          decimal.RequireFromString("128.5"),
+			Close:        decimal.RequireFromString("128.5"),
+			Volume:       5086452,
+			TradeValue:   decimal.NewFromInt(663499104),
+			Change:       decimal.NewFromInt(-1),
+			Transactions: 3815,
+		},
+		"3105": {
+			Date:         date,
+			Open:         decimal.NewFromInt(215),
+			High:         decimal.NewFromInt(216),
+			Low:          decimal.NewFromInt(211),
+			Close:        decimal.RequireFromString("212.5"),
+			Volume:       4567000,
+			TradeValue:   decimal.NewFromInt(973730000),
+			Change:       decimal.Zero,
+			Transactions: 4213,
+		},
+		"6294": {
+			Date:      date,
+			Suspended: true,
+		},
+	}
+	if !cmp.Equal(quotes, want) {
+		t.Errorf("Quote.DownloadTpexDaily returned %+v, want %+v", quotes, want)
+	}
+}
+
+func TestQuoteService_DownloadTpexDailyBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("Quote.DownloadTpexDaily returned %v, want *StatError", err)
+	}
+}
+
+func TestQuoteService_DownloadTpexDailyErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Quote.DownloadTpexDaily returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestQuoteService_DownloadTpexDailyBadTotalCount(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號", "名稱", "收盤", "漲跌", "開盤", "最高", "最低", "均價", "成交股數", "成交金額(元)", "成交筆數"], "data": [], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote data length")
+}
+
+func TestQuoteService_DownloadTpexDailyBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote fields: 代號")
+}
+
+func TestQuoteService_DownloadTpexDailyBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號", "名稱", "收盤", "漲跌", "開盤", "最高", "最低", "均價", "成交股數", "成交金額(元)", "成交筆數"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote fields")
+}
+
+func TestQuoteService_DownloadTpexDailyBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號", "名稱", "收盤", "漲跌", "開盤", "最高", "最低", "均價", "成交股數", "成交金額(元)", "成交筆數"], "data": [["3374", "精材", "1", "0", "1", "1", "1", "1", "BAD", "1", "1"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Quote.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote 3374")
+}
+
+func TestQuoteService_DownloadTpexDailyInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Quote.DownloadTpexDaily(context.Background(), civil.Date{Year: 2007, Month: time.April, Day: 20})
+	if err == nil {
+		t.Fatal("Quote.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2007-04-20")
+}
@@ -0,0 +1,182 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+)
+
+type DayTradingService struct {
+	client *Client
+}
+
+const (
+	// 上市當日沖銷交易標的及成交量值
+	twseDayTradingPath = "/rwd/zh/afterTrading/TWTB4U"
+
+	// 上櫃當日沖銷交易標的及成交量值
+	tpexDayTradingPath = "/www/zh-tw/intraday/stat"
+)
+
+// 個股當日沖銷交易成交量值，日期及代號與 Quote 相同可以直接合併
+type DayTrade struct {
+	Date      civil.Date      // 日期
+	Code      string          // 股票代號
+	Name      string          // 股票名稱
+	Volume    int             // 當日沖銷交易成交股數
+	BuyValue  decimal.Decimal // 當日沖銷交易買進成交金額
+	SellValue decimal.Decimal // 當日沖銷交易賣出成交金額
+	Note      string          // 暫停現股賣出後現款買進當沖註記
+	Suspended bool            // 暫停現股賣出後現款買進當沖，由註記中的 Y 判斷
+}
+
+type twseDayTradingOptions struct {
+	Response   string `url:"response"`
+	Date       string `url:"date"`
+	SelectType string `url:"selectType"`
+}
+
+// 註記中表示暫停現股賣出後現款買進當沖的註記
+const dayTradeSuspendedMarker = "Y"
+
+// dayTradingFields are the headers of the report in the order used by parse,
+// the first table of both reports is the market summary and is skipped since
+// its headers do not match.
+var dayTradingFields = []string{
+	"證券代號",
+	"證券名稱",
+	"暫停現股賣出後現款買進當沖註記",
+	"當日沖銷交易成交股數",
+	"當日沖銷交易買進成交金額",
+	"當日沖銷交易賣出成交金額",
+}
+
+// parse converts a row to DayTrade, indexes are the columns of
+// dayTradingFields in the row.
+func (*DayTradingService) parse(date civil.Date, data []string, indexes []int) (DayTrade, error) {
+	trade := DayTrade{Date: date}
+	for _, i := range indexes {
+		if i >= len(data) {
+			return trade, fmt.Errorf("failed parsing day trade fields")
+		}
+	}
+	trade.Code = strings.TrimSpace(data[indexes[0]])
+	trade.Name = strings.TrimSpace(data[indexes[1]])
+	trade.Note = strings.TrimSpace(data[indexes[2]])
+	trade.Suspended = strings.Contains(trade.Note, dayTradeSuspendedMarker)
+	volume, err := parseVolume(strings.TrimSpace(data[indexes[3]]))
+	if err != nil {
+		return trade, fmt.Errorf("failed parsing day trade %s volume: %w", trade.Code, err)
+	}
+	trade.Volume = volume
+	trade.BuyValue, err = parsePrice(data[indexes[4]])
+	if err != nil {
+		return trade, fmt.Errorf("failed parsing day trade %s buy value: %w", trade.Code, err)
+	}
+	trade.SellValue, err = parsePrice(data[indexes[5]])
+	if err != nil {
+		return trade, fmt.Errorf("failed parsing day trade %s sell value: %w", trade.Code, err)
+	}
+	return trade, nil
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *DayTradingService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所當日沖銷交易標的及成交量值最早到民國103年1月6日
+		return civil.Date{Year: 2014, Month: time.January, Day: 6}
+	}
+	// 證券櫃檯買賣中心當日沖銷交易標的及成交量值最早到民國103年1月6日
+	return civil.Date{Year: 2014, Month: time.January, Day: 6}
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的當日沖銷交易成交量值
+func (s *DayTradingService) DownloadTwse(ctx context.Context, date civil.Date) (map[string]DayTrade, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseDayTradingPath)
+	opts := twseDayTradingOptions{
+		Response:   "json",
+		Date:       fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		SelectType: "All",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	for _, table := range resp.Tables {
+		indexes, err := fieldIndexes(table.Fields, dayTradingFields...)
+		if err != nil {
+			continue
+		}
+		trades := map[string]DayTrade{}
+		for _, data := range table.Data {
+			trade, err := s.parse(date, data, indexes)
+			if err != nil {
+				return nil, err
+			}
+			trades[trade.Code] = trade
+		}
+		return trades, nil
+	}
+	return nil, fmt.Errorf("failed parsing day trading fields: day trading table not found")
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的當日沖銷交易成交量值
+func (s *DayTradingService) DownloadTpex(ctx context.Context, date civil.Date) (map[string]DayTrade, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexDayTradingPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 {
+		return nil, ErrNoData
+	}
+	for _, table := range resp.Tables {
+		indexes, err := fieldIndexes(table.Fields, dayTradingFields...)
+		if err != nil {
+			continue
+		}
+		if table.TotalCount == 0 {
+			return nil, ErrNoData
+		}
+		date, err := tpexTableDate(date, table.Date)
+		if err != nil {
+			return nil, err
+		}
+		trades := map[string]DayTrade{}
+		for _, row := range table.Data {
+			trade, err := s.parse(date, tpexStrings(row), indexes)
+			if err != nil {
+				return nil, err
+			}
+			trades[trade.Code] = trade
+		}
+		return trades, nil
+	}
+	return nil, fmt.Errorf("failed parsing day trading fields: day trading table not found")
+}
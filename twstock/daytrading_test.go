@@ -0,0 +1,335 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+const testDayTradingFields = `["證券代號", "證券名稱", "暫停現股賣出後現款買進當沖註記", "當日沖銷交易成交股數", "當日沖銷交易買進成交金額", "當日沖銷交易賣出成交金額"]`
+
+func TestDayTradingService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("selectType"), "All"; got != want {
+			t.Errorf("Request selectType = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"tables": [
+				{
+					"title": "111年08月22日 當日沖銷交易統計資訊",
+					"fields": ["當日沖銷交易總成交股數", "當日沖銷交易總成交股數占市場比重%", "當日沖銷交易總買進成交金額", "當日沖銷交易總買進成交金額占市場比重%", "當日沖銷交易總賣出成交金額", "當日沖銷交易總賣出成交金額占市場比重%"],
+					"data": [["1,079,011,000", "28.74", "44,542,713,240", "18.93", "44,586,404,830", "18.95"]]
+				},
+				{
+					"title": "111年08月22日 當日沖銷交易標的及成交量值",
+					"fields": `+testDayTradingFields+`,
+					"data": [
+						["2330", "台積電", "", "6,436,000", "3,359,484,500", "3,361,167,000"],
+						["1213", "大飲", "Y", "0", "0", "0"]
+					]
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	trades, err := client.DayTrading.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DayTrading.DownloadTwse returned error: %v", err)
+	}
+	want := map[string]DayTrade{
+		"2330": {
+			Date:      date,
+			Code:      "2330",
+			Name:      "台積電",
+			Volume:    6436000,
+			BuyValue:  decimal.NewFromInt(3359484500),
+			SellValue: decimal.NewFromInt(3361167000),
+		},
+		"1213": {
+			Date:      date,
+			Code:      "1213",
+			Name:      "大飲",
+			BuyValue:  decimal.Zero,
+			SellValue: decimal.Zero,
+			Note:      "Y",
+			Suspended: true,
+		},
+	}
+	if !cmp.Equal(trades, want) {
+		t.Errorf("DayTrading.DownloadTwse returned %+v, want %+v", trades, want)
+	}
+}
+
+func TestDayTradingService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("DayTrading.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestDayTradingService_DownloadTwseNoTradesTable(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["當日沖銷交易總成交股數"], "data": []}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	if errors.Is(err, ErrNoData) {
+		t.Errorf("DayTrading.DownloadTwse returned %v, want fields error", err)
+	}
+	testErrorContains(t, err, "failed parsing day trading fields: day trading table not found")
+}
+
+func TestDayTradingService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testDayTradingFields+`, "data": [["2330", "台積電"]]}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing day trade fields")
+}
+
+func TestDayTradingService_DownloadTwseBadVolume(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testDayTradingFields+`, "data": [["2330", "台積電", "", "BAD", "0", "0"]]}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing day trade 2330 volume")
+}
+
+func TestDayTradingService_DownloadTwseBadBuyValue(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testDayTradingFields+`, "data": [["2330", "台積電", "", "0", "BAD", "0"]]}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing day trade 2330 buy value")
+}
+
+func TestDayTradingService_DownloadTwseBadSellValue(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testDayTradingFields+`, "data": [["2330", "台積電", "", "0", "0", "BAD"]]}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing day trade 2330 sell value")
+}
+
+func TestDayTradingService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.DayTrading.DownloadTwse(context.Background(), civil.Date{Year: 2014, Month: time.January, Day: 3})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2014-01-03")
+}
+
+func TestDayTradingService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"title": "當日沖銷交易統計資訊",
+					"fields": ["當日沖銷交易總成交股數", "當日沖銷交易總買進成交金額", "當日沖銷交易總賣出成交金額"],
+					"data": [["217,350,164", "10,512,332,790", "10,546,139,600"]],
+					"totalCount": 1
+				},
+				{
+					"title": "當日沖銷交易標的及成交量值",
+					"fields": `+testDayTradingFields+`,
+					"data": [["3374", "精材", " ", 158000, "13,873,500", "13,900,500"]],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	trades, err := client.DayTrading.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("DayTrading.DownloadTpex returned error: %v", err)
+	}
+	want := map[string]DayTrade{
+		"3374": {
+			Date:      date,
+			Code:      "3374",
+			Name:      "精材",
+			Volume:    158000,
+			BuyValue:  decimal.NewFromInt(13873500),
+			SellValue: decimal.NewFromInt(13900500),
+		},
+	}
+	if !cmp.Equal(trades, want) {
+		t.Errorf("DayTrading.DownloadTpex returned %+v, want %+v", trades, want)
+	}
+}
+
+func TestDayTradingService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("DayTrading.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestDayTradingService_DownloadTpexNoTradesTable(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["當日沖銷交易總成交股數"], "totalCount": 1}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTpex returned nil; expected error")
+	}
+	if errors.Is(err, ErrNoData) {
+		t.Errorf("DayTrading.DownloadTpex returned %v, want fields error", err)
+	}
+	testErrorContains(t, err, "failed parsing day trading fields: day trading table not found")
+}
+
+func TestDayTradingService_DownloadTpexReportDate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"date": "111/08/19", "fields": `+testDayTradingFields+`, "data": [["3374", "精材", " ", 158000, "13,873,500", "13,900,500"]], "totalCount": 1}]}`)
+	})
+
+	trades, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err != nil {
+		t.Fatalf("DayTrading.DownloadTpex returned error: %v", err)
+	}
+	if got, want := trades["3374"].Date, (civil.Date{Year: 2022, Month: time.August, Day: 19}); got != want {
+		t.Errorf("DayTrading.DownloadTpex date = %v, want %v", got, want)
+	}
+}
+
+func TestDayTradingService_DownloadTpexNoTables(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": []}`)
+	})
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("DayTrading.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestDayTradingService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testDayTradingFields+`, "totalCount": 0}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("DayTrading.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestDayTradingService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDayTradingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testDayTradingFields+`, "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing day trade fields")
+}
+
+func TestDayTradingService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.DayTrading.DownloadTpex(context.Background(), civil.Date{Year: 2014, Month: time.January, Day: 3})
+	if err == nil {
+		t.Fatal("DayTrading.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2014-01-03")
+}
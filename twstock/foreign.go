@@ -0,0 +1,237 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+)
+
+type ForeignHoldingService struct {
+	client *Client
+}
+
+const (
+	// 上市外資及陸資投資持股統計
+	twseForeignHoldingPath = "/rwd/zh/fund/MI_QFIIS"
+
+	// 上櫃外資及陸資投資持股統計
+	tpexForeignHoldingPath = "/www/zh-tw/insti/qfii"
+)
+
+// 個股外資及陸資持股
+type ForeignHolding struct {
+	Date            civil.Date      // 日期
+	Code            string          // 股票代號
+	Name            string          // 股票名稱
+	SharesIssued    int             // 發行股數
+	AvailableShares int             // 外資及陸資尚可投資股數
+	HeldShares      int             // 全體外資及陸資持有股數
+	AvailableRatio  decimal.Decimal // 外資及陸資尚可投資比率（%）
+	HeldRatio       decimal.Decimal // 全體外資及陸資持股比率（%）
+	UpperLimitRatio decimal.Decimal // 外資及陸資法令投資上限比率（%）
+}
+
+type twseForeignHoldingOptions struct {
+	Response   string `url:"response"`
+	Date       string `url:"date"`
+	SelectType string `url:"selectType"`
+}
+
+// foreignHoldingFields are the headers shared by both reports in the order of
+// the fields of ForeignHolding after the code.
+var foreignHoldingFields = []string{
+	"發行股數",
+	"外資及陸資尚可投資股數",
+	"全體外資及陸資持有股數",
+	"外資及陸資尚可投資比率",
+	"全體外資及陸資持股比率",
+}
+
+// parse converts a row to ForeignHolding, indexes are the columns of the code,
+// the name, foreignHoldingFields and the upper limit in the row.
+func (*ForeignHoldingService) parse(date civil.Date, data []string, indexes []int) (ForeignHolding, error) {
+	holding := ForeignHolding{Date: date}
+	for _, i := range indexes {
+		if i >= len(data) {
+			return holding, fmt.Errorf("failed parsing foreign holding fields")
+		}
+	}
+	holding.Code = strings.TrimSpace(data[indexes[0]])
+	holding.Name = strings.TrimSpace(data[indexes[1]])
+	shares := []struct {
+		name  string
+		value *int
+	}{
+		{"shares issued", &holding.SharesIssued},
+		{"available shares", &holding.AvailableShares},
+		{"held shares", &holding.HeldShares},
+	}
+	for i, f := range shares {
+		v, err := parseVolume(strings.TrimSpace(data[indexes[i+2]]))
+		if err != nil {
+			return holding, fmt.Errorf("failed parsing foreign holding %s: %w", f.name, err)
+		}
+		*f.value = v
+	}
+	ratios := []struct {
+		name  string
+		value *decimal.Decimal
+	}{
+		{"available ratio", &holding.AvailableRatio},
+		{"held ratio", &holding.HeldRatio},
+		{"upper limit ratio", &holding.UpperLimitRatio},
+	}
+	for i, f := range ratios {
+		v, err := parseRatio(data[indexes[i+5]])
+		if err != nil {
+			return holding, fmt.Errorf("failed parsing foreign holding %s: %w", f.name, err)
+		}
+		*f.value = v
+	}
+	return holding, nil
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *ForeignHoldingService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所外資及陸資投資持股統計最早到民國93年2月11日
+		return civil.Date{Year: 2004, Month: time.February, Day: 11}
+	}
+	// 證券櫃檯買賣中心外資及陸資投資持股統計最早到民國96年4月23日
+	return civil.Date{Year: 2007, Month: time.April, Day: 23}
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的外資及陸資持股
+func (s *ForeignHoldingService) DownloadTwse(ctx context.Context, date civil.Date) (map[string]ForeignHolding, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseForeignHoldingPath)
+	opts := twseForeignHoldingOptions{
+		Response:   "json",
+		Date:       fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		SelectType: "ALLBUT0999",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	names := append([]string{"證券代號", "證券名稱"}, foreignHoldingFields...)
+	indexes, err := fieldIndexes(resp.Fields, append(names, "外資及陸資共用法令投資上限比率")...)
+	if err != nil {
+		return nil, err
+	}
+	holdings := map[string]ForeignHolding{}
+	for _, data := range resp.Data {
+		holding, err := s.parse(date, data, indexes)
+		if err != nil {
+			return nil, err
+		}
+		holdings[holding.Code] = holding
+	}
+	return holdings, nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的外資及陸資持股
+func (s *ForeignHoldingService) DownloadTpex(ctx context.Context, date civil.Date) (map[string]ForeignHolding, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexForeignHoldingPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	names := append([]string{"代號", "名稱"}, foreignHoldingFields...)
+	indexes, err := fieldIndexes(table.Fields, append(names, "法令投資上限比率")...)
+	if err != nil {
+		return nil, err
+	}
+	date, err = tpexTableDate(date, table.Date)
+	if err != nil {
+		return nil, err
+	}
+	holdings := map[string]ForeignHolding{}
+	for _, row := range table.Data {
+		holding, err := s.parse(date, tpexStrings(row), indexes)
+		if err != nil {
+			return nil, err
+		}
+		holdings[holding.Code] = holding
+	}
+	return holdings, nil
+}
+
+// 從台灣證卷交易所或證券櫃檯買賣中心下載個股 from 到 to（包含）之間每日的外資及陸資持股。
+// 每個交易日都需要下載全市場的資料，週末、沒有資料的休市日及尚未公布的日期會被略過
+func (s *ForeignHoldingService) History(ctx context.Context, code string, from, to civil.Date) ([]ForeignHolding, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: %s - %s", from, to)
+	}
+	//nolint:typecheck
+	security, ok := Securities[code]
+	if !ok {
+		return nil, fmt.Errorf("invalid code: %s", code)
+	}
+	var download func(context.Context, civil.Date) (map[string]ForeignHolding, error)
+	switch security.Market {
+	case TWSE:
+		download = s.DownloadTwse
+	case TPEx:
+		download = s.DownloadTpex
+	default:
+		return nil, fmt.Errorf("invalid market: %s", security.Market)
+	}
+	if minimumDate := s.MinimumDate(security.Market); from.Before(minimumDate) {
+		from = minimumDate
+	}
+	if now := today(); to.After(now) {
+		to = now
+	}
+
+	holdings := []ForeignHolding{}
+	for date := from; !to.Before(date); date = date.AddDays(1) {
+		if weekday := date.In(time.UTC).Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			continue
+		}
+		result, err := download(ctx, date)
+		// 當日資料尚未公布時回傳 ErrDateOutOffRange
+		if errors.Is(err, ErrNoData) || errors.Is(err, ErrDateOutOffRange) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		holding, ok := result[code]
+		// 證券櫃檯買賣中心的日期取自報表，避免同一份報表重複加入
+		if !ok || len(holdings) > 0 && holdings[len(holdings)-1].Date == holding.Date {
+			continue
+		}
+		holdings = append(holdings, holding)
+	}
+	return holdings, nil
+}
@@ -0,0 +1,344 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	testTwseForeignHoldingFields = `["證券代號", "證券名稱", "國際證券編碼", "發行股數", "外資及陸資尚可投資股數", "全體外資及陸資持有股數", "外資及陸資尚可投資比率", "全體外資及陸資持股比率", "外資及陸資共用法令投資上限比率", "陸資法令投資上限比率", "與前日異動原因(註)", "最近一次上市公司申報外資持股異動日期"]`
+	testTpexForeignHoldingFields = `["排行", "代號", "名稱", "發行股數", "外資及陸資尚可投資股數", "全體外資及陸資持有股數", "外資及陸資尚可投資比率", "全體外資及陸資持股比率", "法令投資上限比率", "與前日異動原因", "最近一次上櫃公司申報外資持股異動日期"]`
+)
+
+func TestForeignHoldingService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("selectType"), "ALLBUT0999"; got != want {
+			t.Errorf("Request selectType = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"fields": `+testTwseForeignHoldingFields+`,
+			"data": [
+				["2330", "台積電", "TW0002330008", "25,930,380,458", "7,292,114,196", "18,638,266,262", "28.12", "71.87", "100.00", "100.00", "", "111/08/05"]
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	holdings, err := client.ForeignHolding.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("ForeignHolding.DownloadTwse returned error: %v", err)
+	}
+	want := map[string]ForeignHolding{
+		"2330": {
+			Date:            date,
+			Code:            "2330",
+			Name:            "台積電",
+			SharesIssued:    25930380458,
+			AvailableShares: 7292114196,
+			HeldShares:      18638266262,
+			AvailableRatio:  decimal.RequireFromString("28.12"),
+			HeldRatio:       decimal.RequireFromString("71.87"),
+			UpperLimitRatio: decimal.NewFromInt(100),
+		},
+	}
+	if !cmp.Equal(holdings, want) {
+		t.Errorf("ForeignHolding.DownloadTwse returned %+v, want %+v", holdings, want)
+	}
+}
+
+func TestForeignHoldingService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("ForeignHolding.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestForeignHoldingService_DownloadTwseBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["證券代號"], "data": []}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 證券代號")
+}
+
+func TestForeignHoldingService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseForeignHoldingFields+`, "data": [["2330"]]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing foreign holding fields")
+}
+
+func TestForeignHoldingService_DownloadTwseBadShares(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseForeignHoldingFields+`, "data": [["2330", "台積電", "", "BAD", "1", "1", "1", "1", "1", "1", "", ""]]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing foreign holding shares issued")
+}
+
+func TestForeignHoldingService_DownloadTwseBadRatio(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseForeignHoldingFields+`, "data": [["2330", "台積電", "", "1", "1", "1", "1", "BAD", "1", "1", "", ""]]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing foreign holding held ratio")
+}
+
+func TestForeignHoldingService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.ForeignHolding.DownloadTwse(context.Background(), civil.Date{Year: 2004, Month: time.February, Day: 10})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2004-02-10")
+}
+
+func TestForeignHoldingService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"fields": `+testTpexForeignHoldingFields+`,
+					"data": [["1", "3374", "精材", "271,680,000", "254,125,312", 17554688, "93.53", "6.46", "100.00", "", "111/08/19"]],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	holdings, err := client.ForeignHolding.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("ForeignHolding.DownloadTpex returned error: %v", err)
+	}
+	want := map[string]ForeignHolding{
+		"3374": {
+			Date:            date,
+			Code:            "3374",
+			Name:            "精材",
+			SharesIssued:    271680000,
+			AvailableShares: 254125312,
+			HeldShares:      17554688,
+			AvailableRatio:  decimal.RequireFromString("93.53"),
+			HeldRatio:       decimal.RequireFromString("6.46"),
+			UpperLimitRatio: decimal.NewFromInt(100),
+		},
+	}
+	if !cmp.Equal(holdings, want) {
+		t.Errorf("ForeignHolding.DownloadTpex returned %+v, want %+v", holdings, want)
+	}
+}
+
+func TestForeignHoldingService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("ForeignHolding.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestForeignHoldingService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("ForeignHolding.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestForeignHoldingService_DownloadTpexBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 代號")
+}
+
+func TestForeignHoldingService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexForeignHoldingFields+`, "data": [["1", "3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.ForeignHolding.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing foreign holding fields")
+}
+
+func TestForeignHoldingService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.ForeignHolding.DownloadTpex(context.Background(), civil.Date{Year: 2007, Month: time.April, Day: 20})
+	if err == nil {
+		t.Fatal("ForeignHolding.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2007-04-20")
+}
+
+func TestForeignHoldingService_History(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	dates := []string{}
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		date := r.URL.Query().Get("date")
+		dates = append(dates, date)
+		// 休市日
+		if date == "20220823" {
+			fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseForeignHoldingFields+`, "data": [["2330", "台積電", "", "100", "30", "70", "30.00", "70.00", "100.00", "100.00", "", ""]]}`)
+	})
+	mux.HandleFunc(tpexForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	// 2022/08/19 為星期五，週末不會發出請求
+	from := civil.Date{Year: 2022, Month: time.August, Day: 19}
+	to := civil.Date{Year: 2022, Month: time.August, Day: 23}
+	holdings, err := client.ForeignHolding.History(context.Background(), "2330", from, to)
+	if err != nil {
+		t.Fatalf("ForeignHolding.History returned error: %v", err)
+	}
+	if want := []string{"20220819", "20220822", "20220823"}; !cmp.Equal(dates, want) {
+		t.Errorf("ForeignHolding.History requested %v, want %v", dates, want)
+	}
+	if len(holdings) != 2 || holdings[0].Date != from || holdings[1].Date != from.AddDays(3) {
+		t.Errorf("ForeignHolding.History returned %+v, want holdings on %s and %s", holdings, from, from.AddDays(3))
+	}
+
+	_, err = client.ForeignHolding.History(context.Background(), "3374", from, to)
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("ForeignHolding.History returned %v, want *StatError", err)
+	}
+	_, err = client.ForeignHolding.History(context.Background(), "BAD", from, to)
+	if err == nil {
+		t.Fatal("ForeignHolding.History returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid code: BAD")
+	_, err = client.ForeignHolding.History(context.Background(), "2330", to, from)
+	if err == nil {
+		t.Fatal("ForeignHolding.History returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date range")
+}
+
+func TestForeignHoldingService_HistoryFuture(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	now := today()
+	published := 0
+	mux.HandleFunc(twseForeignHoldingPath, func(w http.ResponseWriter, r *http.Request) {
+		date := r.URL.Query().Get("date")
+		if date > fmt.Sprintf("%04d%02d%02d", now.Year, now.Month, now.Day) {
+			t.Errorf("ForeignHolding.History requested %v, want no date after %v", date, now)
+		}
+		// 當日資料尚未公布
+		if date == fmt.Sprintf("%04d%02d%02d", now.Year, now.Month, now.Day) {
+			fmt.Fprint(w, `{"stat": "查詢日期大於今日，請重新查詢!"}`)
+			return
+		}
+		published++
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseForeignHoldingFields+`, "data": [["2330", "台積電", "", "100", "30", "70", "30.00", "70.00", "100.00", "100.00", "", ""]]}`)
+	})
+
+	holdings, err := client.ForeignHolding.History(context.Background(), "2330", now.AddDays(-7), civil.Date{Year: 2100, Month: time.January, Day: 1})
+	if err != nil {
+		t.Fatalf("ForeignHolding.History returned error: %v", err)
+	}
+	if len(holdings) != published {
+		t.Errorf("ForeignHolding.History returned %d holdings, want %d", len(holdings), published)
+	}
+}
@@ -0,0 +1,115 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 常用的即時指數代號，t 開頭為上市指數（類股指數為 t01 之後），o 開頭為上櫃指數
+const (
+	TAIEXCode     = "t00" // 發行量加權股價指數
+	TPExIndexCode = "o00" // 櫃買指數
+)
+
+type RealtimeIndex struct {
+	At            time.Time       // 最新一筆指數時間
+	Code          string          // 指數代號
+	Name          string          // 指數名稱
+	Value         decimal.Decimal // 目前指數，尚未開盤時為昨收指數
+	Open          decimal.Decimal // 開盤指數
+	High          decimal.Decimal // 最高指數
+	Low           decimal.Decimal // 最低指數
+	PreviousClose decimal.Decimal // 昨收指數
+	Change        decimal.Decimal // 漲跌點數
+	ChangePercent decimal.Decimal // 漲跌幅（%），四捨五入到小數第二位
+	Exchange      Market          // 市場別
+}
+
+// indexChannel returns the market information system channel of an index
+// code such as t00 or o00.
+func indexChannel(code string) (string, error) {
+	switch {
+	case strings.HasPrefix(code, "t"):
+		return fmt.Sprintf("%s_%s.tw", TWSE, code), nil
+	case strings.HasPrefix(code, "o"):
+		return fmt.Sprintf("%s_%s.tw", TPEx, code), nil
+	}
+	return "", fmt.Errorf("invalid index code: %s", code)
+}
+
+func parseRealtimeIndex(data realtimeData) (RealtimeIndex, error) {
+	var index RealtimeIndex
+	fields := []struct {
+		name  string
+		raw   string
+		value *decimal.Decimal
+	}{
+		{"open", data.Open, &index.Open},
+		{"high", data.High, &index.High},
+		{"low", data.Low, &index.Low},
+		{"previous close", data.PreviousClose, &index.PreviousClose},
+	}
+	for _, f := range fields {
+		v, _, err := parseRealtimePrice(f.raw)
+		if err != nil {
+			return index, fmt.Errorf("failed parsing index %s: %w", f.name, err)
+		}
+		*f.value = v
+	}
+	value, ok, err := parseRealtimePrice(data.Price)
+	if err != nil {
+		return index, fmt.Errorf("failed parsing index value: %w", err)
+	}
+	if ok {
+		index.Value = value
+		if !index.PreviousClose.IsZero() {
+			index.Change = value.Sub(index.PreviousClose)
+			index.ChangePercent = index.Change.Div(index.PreviousClose).Mul(decimal.NewFromInt(100)).Round(2)
+		}
+	} else {
+		index.Value = index.PreviousClose
+	}
+	index.At = data.at()
+	index.Code = data.Code
+	index.Name = data.Name
+	index.Exchange = Market(data.Exchange)
+	return index, nil
+}
+
+// 從台灣證卷交易所下載即時指數，例如加權指數 TAIEXCode、櫃買指數 TPExIndexCode 或類股指數 t13，
+// 部分代號查詢失敗時仍會回傳其餘代號的結果以及 CodeErrors
+func (s *QuoteService) RealtimeIndex(ctx context.Context, codes ...string) (map[string]RealtimeIndex, error) {
+	channels := make([]string, 0, len(codes))
+	for _, code := range codes {
+		channel, err := indexChannel(code)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	result, errs, err := s.realtime(ctx, realtimeQuotesPath, channels, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := map[string]RealtimeIndex{}
+	for _, data := range result {
+		index, err := parseRealtimeIndex(data)
+		if err != nil {
+			errs[data.Code] = err
+			continue
+		}
+		indices[data.Code] = index
+	}
+	for _, code := range codes {
+		if _, ok := indices[code]; !ok && errs[code] == nil {
+			errs[code] = fmt.Errorf("%w: %s", ErrNoData, code)
+		}
+	}
+	return indices, errs.errOrNil()
+}
@@ -0,0 +1,120 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestQuoteService_RealtimeIndex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("ex_ch"), "tse_t00.tw|otc_o00.tw|tse_t13.tw"; got != want {
+			t.Errorf("Request ex_ch = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"msgArray": [
+				{
+					"c": "t00",
+					"n": "發行量加權股價指數",
+					"ex": "tse",
+					"tlong": "1661149800000",
+					"z": "15081.4400",
+					"o": "15320.5400",
+					"h": "15320.5400",
+					"l": "15081.4400",
+					"y": "15408.7800"
+				},
+				{
+					"c": "o00",
+					"n": "櫃買指數",
+					"ex": "otc",
+					"tlong": "1661149800000",
+					"z": "-",
+					"o": "-",
+					"h": "-",
+					"l": "-",
+					"y": "170.5000"
+				}
+			],
+			"rtmessage": "OK"
+		}`)
+	})
+
+	indices, err := client.Quote.RealtimeIndex(context.Background(), TAIEXCode, TPExIndexCode, "t13")
+	var codeErrs CodeErrors
+	if !errors.As(err, &codeErrs) {
+		t.Fatalf("Quote.RealtimeIndex returned error %v, want CodeErrors", err)
+	}
+	if len(codeErrs) != 1 || !errors.Is(codeErrs["t13"], ErrNoData) {
+		t.Errorf("Quote.RealtimeIndex returned %v, want t13 %v", codeErrs, ErrNoData)
+	}
+	want := map[string]RealtimeIndex{
+		"t00": {
+			At:            time.Date(2022, 8, 22, 14, 30, 0, 0, taipei),
+			Code:          "t00",
+			Name:          "發行量加權股價指數",
+			Value:         decimal.RequireFromString("15081.44"),
+			Open:          decimal.RequireFromString("15320.54"),
+			High:          decimal.RequireFromString("15320.54"),
+			Low:           decimal.RequireFromString("15081.44"),
+			PreviousClose: decimal.RequireFromString("15408.78"),
+			Change:        decimal.RequireFromString("-327.34"),
+			ChangePercent: decimal.RequireFromString("-2.12"),
+			Exchange:      TWSE,
+		},
+		"o00": {
+			At:            time.Date(2022, 8, 22, 14, 30, 0, 0, taipei),
+			Code:          "o00",
+			Name:          "櫃買指數",
+			Value:         decimal.RequireFromString("170.5"),
+			PreviousClose: decimal.RequireFromString("170.5"),
+			Exchange:      TPEx,
+		},
+	}
+	if !cmp.Equal(indices, want) {
+		t.Errorf("Quote.RealtimeIndex returned %v, want %v", indices, want)
+	}
+}
+
+func TestQuoteService_RealtimeIndexError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"msgArray": [{"c": "t00", "z": "BAD"}], "rtmessage": "OK"}`)
+	})
+
+	_, err := client.Quote.RealtimeIndex(context.Background(), TAIEXCode)
+	if err == nil {
+		t.Error("Quote.RealtimeIndex returned nil; expected error")
+	}
+
+	_, err = client.Quote.RealtimeIndex(context.Background(), "2330")
+	if err == nil {
+		t.Error("Quote.RealtimeIndex returned nil; expected error")
+	}
+}
+
+func TestParseRealtimeIndex(t *testing.T) {
+	for _, data := range []realtimeData{
+		{Open: "BAD"},
+		{High: "BAD"},
+		{Low: "BAD"},
+		{PreviousClose: "BAD"},
+	} {
+		if _, err := parseRealtimeIndex(data); err == nil {
+			t.Errorf("parseRealtimeIndex(%+v) returned nil; expected error", data)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+)
+
+type InstitutionalService struct {
+	client *Client
+}
+
+const (
+	// 上市三大法人買賣超日報
+	twseInstitutionalPath = "/rwd/zh/fund/T86"
+
+	// 上市三大法人買賣金額統計表
+	twseInstitutionalSummaryPath = "/rwd/zh/fund/BFI82U"
+
+	// 上櫃三大法人買賣明細資訊
+	tpexInstitutionalPath = "/www/zh-tw/insti/dailyTrade"
+)
+
+// 個股三大法人買賣超，單位為股
+type InstitutionalTrade struct {
+	Date civil.Date // 日期
+	Code string     // 股票代號
+	Name string     // 股票名稱
+
+	ForeignBuy  int // 外陸資買進股數（不含外資自營商）
+	ForeignSell int // 外陸資賣出股數（不含外資自營商）
+	ForeignNet  int // 外陸資買賣超股數（不含外資自營商）
+
+	ForeignDealerBuy  int // 外資自營商買進股數
+	ForeignDealerSell int // 外資自營商賣出股數
+	ForeignDealerNet  int // 外資自營商買賣超股數
+
+	InvestmentTrustBuy  int // 投信買進股數
+	InvestmentTrustSell int // 投信賣出股數
+	InvestmentTrustNet  int // 投信買賣超股數
+
+	DealerNet int // 自營商買賣超股數
+
+	DealerProprietaryBuy  int // 自營商買進股數（自行買賣）
+	DealerProprietarySell int // 自營商賣出股數（自行買賣）
+	DealerProprietaryNet  int // 自營商買賣超股數（自行買賣）
+
+	DealerHedgeBuy  int // 自營商買進股數（避險）
+	DealerHedgeSell int // 自營商賣出股數（避險）
+	DealerHedgeNet  int // 自營商買賣超股數（避險）
+
+	TotalNet int // 三大法人買賣超股數
+}
+
+// 三大法人買賣金額統計
+type InstitutionalSummary struct {
+	Name string          // 單位名稱，例如投信、外資及陸資(不含外資自營商)或合計
+	Buy  decimal.Decimal // 買進金額
+	Sell decimal.Decimal // 賣出金額
+	Net  decimal.Decimal // 買賣差額
+}
+
+type twseInstitutionalOptions struct {
+	Response   string `url:"response"`
+	Date       string `url:"date"`
+	SelectType string `url:"selectType"`
+}
+
+type twseInstitutionalSummaryOptions struct {
+	Response string `url:"response"`
+	Date     string `url:"dayDate"`
+	Type     string `url:"type"`
+}
+
+type tpexInstitutionalOptions struct {
+	Response string `url:"response"`
+	Date     string `url:"date"`
+	Type     string `url:"type"`
+	Sect     string `url:"sect"`
+}
+
+// 上市三大法人買賣超日報的欄位，依序對應 InstitutionalTrade 的股數欄位
+var twseInstitutionalFields = []string{
+	"外陸資買進股數(不含外資自營商)",
+	"外陸資賣出股數(不含外資自營商)",
+	"外陸資買賣超股數(不含外資自營商)",
+	"外資自營商買進股數",
+	"外資自營商賣出股數",
+	"外資自營商買賣超股數",
+	"投信買進股數",
+	"投信賣出股數",
+	"投信買賣超股數",
+	"自營商買賣超股數",
+	"自營商買進股數(自行買賣)",
+	"自營商賣出股數(自行買賣)",
+	"自營商買賣超股數(自行買賣)",
+	"自營商買進股數(避險)",
+	"自營商賣出股數(避險)",
+	"自營商買賣超股數(避險)",
+	"三大法人買賣超股數",
+}
+
+// 上櫃三大法人買賣明細的欄位，各單位的買進、賣出及買賣超欄位名稱相同，
+// 依序為外資及陸資（不含外資自營商）、外資自營商、外資及陸資合計、投信、
+// 自營商（自行買賣）、自營商（避險）、自營商合計及三大法人合計，只能依位置解析
+var tpexInstitutionalFields = []string{
+	"代號", "名稱",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"買進股數", "賣出股數", "買賣超股數",
+	"三大法人買賣超股數合計",
+}
+
+// tpexInstitutionalColumns are the columns of the TPEx report in the order of
+// the share fields of InstitutionalTrade, the totals of foreign investors and
+// dealers are skipped except for the dealer net.
+var tpexInstitutionalColumns = []int{2, 3, 4, 5, 6, 7, 11, 12, 13, 22, 14, 15, 16, 17, 18, 19, 23}
+
+// checkTpexInstitutionalFields validates every header of the TPEx report
+// since the columns are parsed by position.
+func checkTpexInstitutionalFields(fields []string) error {
+	trimmed := make([]string, len(fields))
+	for i, v := range fields {
+		trimmed[i] = strings.TrimSpace(v)
+	}
+	if len(trimmed) != len(tpexInstitutionalFields) {
+		return fmt.Errorf("failed parsing institutional trade fields: %s", strings.Join(trimmed, ","))
+	}
+	for i, name := range tpexInstitutionalFields {
+		if trimmed[i] != name {
+			return fmt.Errorf("failed parsing institutional trade fields: %s", strings.Join(trimmed, ","))
+		}
+	}
+	return nil
+}
+
+// shares returns pointers to the share fields in the order of
+// twseInstitutionalFields.
+func (t *InstitutionalTrade) shares() []*int {
+	return []*int{
+		&t.ForeignBuy, &t.ForeignSell, &t.ForeignNet,
+		&t.ForeignDealerBuy, &t.ForeignDealerSell, &t.ForeignDealerNet,
+		&t.InvestmentTrustBuy, &t.InvestmentTrustSell, &t.InvestmentTrustNet,
+		&t.DealerNet,
+		&t.DealerProprietaryBuy, &t.DealerProprietarySell, &t.DealerProprietaryNet,
+		&t.DealerHedgeBuy, &t.DealerHedgeSell, &t.DealerHedgeNet,
+		&t.TotalNet,
+	}
+}
+
+// parse converts a row to InstitutionalTrade, columns are the indexes of the
+// share fields in the row.
+func (*InstitutionalService) parse(date civil.Date, data []string, code, name int, columns []int) (InstitutionalTrade, error) {
+	trade := InstitutionalTrade{Date: date}
+	if len(data) <= code || len(data) <= name {
+		return trade, fmt.Errorf("failed parsing institutional trade fields")
+	}
+	trade.Code = strings.TrimSpace(data[code])
+	trade.Name = strings.TrimSpace(data[name])
+	for i, v := range trade.shares() {
+		if columns[i] >= len(data) {
+			return trade, fmt.Errorf("failed parsing institutional trade fields")
+		}
+		shares, err := parseVolume(strings.TrimSpace(data[columns[i]]))
+		if err != nil {
+			return trade, fmt.Errorf("failed parsing institutional trade %s: %w", trade.Code, err)
+		}
+		*v = shares
+	}
+	return trade, nil
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *InstitutionalService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所自民國106年12月18日起分別揭露外資及外資自營商
+		return civil.Date{Year: 2017, Month: time.December, Day: 18}
+	}
+	// 證券櫃檯買賣中心自民國107年1月15日起分別揭露外資及外資自營商
+	return civil.Date{Year: 2018, Month: time.January, Day: 15}
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的三大法人買賣超
+func (s *InstitutionalService) DownloadTwse(ctx context.Context, date civil.Date) (map[string]InstitutionalTrade, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseInstitutionalPath)
+	opts := twseInstitutionalOptions{
+		Response:   "json",
+		Date:       fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		SelectType: "ALLBUT0999",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	indexes, err := fieldIndexes(resp.Fields, append([]string{"證券代號", "證券名稱"}, twseInstitutionalFields...)...)
+	if err != nil {
+		return nil, err
+	}
+	trades := map[string]InstitutionalTrade{}
+	for _, data := range resp.Data {
+		trade, err := s.parse(date, data, indexes[0], indexes[1], indexes[2:])
+		if err != nil {
+			return nil, err
+		}
+		trades[trade.Code] = trade
+	}
+	return trades, nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的三大法人買賣超
+func (s *InstitutionalService) DownloadTpex(ctx context.Context, date civil.Date) (map[string]InstitutionalTrade, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexInstitutionalPath)
+	opts := tpexInstitutionalOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+		Type:     "Daily",
+		Sect:     "EW",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	if err := checkTpexInstitutionalFields(table.Fields); err != nil {
+		return nil, err
+	}
+	date, err = tpexTableDate(date, table.Date)
+	if err != nil {
+		return nil, err
+	}
+	trades := map[string]InstitutionalTrade{}
+	for _, row := range table.Data {
+		trade, err := s.parse(date, tpexStrings(row), 0, 1, tpexInstitutionalColumns)
+		if err != nil {
+			return nil, err
+		}
+		trades[trade.Code] = trade
+	}
+	return trades, nil
+}
+
+// 從台灣證卷交易所下載指定日期的三大法人買賣金額統計
+func (s *InstitutionalService) DownloadTwseSummary(ctx context.Context, date civil.Date) ([]InstitutionalSummary, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseInstitutionalSummaryPath)
+	opts := twseInstitutionalSummaryOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		Type:     "day",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Fields) != 4 ||
+		resp.Fields[0] != "單位名稱" ||
+		resp.Fields[1] != "買進金額" ||
+		resp.Fields[2] != "賣出金額" ||
+		resp.Fields[3] != "買賣差額" {
+		return nil, fmt.Errorf("failed parsing institutional summary fields: %s", strings.Join(resp.Fields, ","))
+	}
+	summaries := []InstitutionalSummary{}
+	for _, data := range resp.Data {
+		if len(data) != 4 {
+			return nil, fmt.Errorf("failed parsing institutional summary fields")
+		}
+		summary := InstitutionalSummary{Name: strings.TrimSpace(data[0])}
+		fields := []struct {
+			name  string
+			value *decimal.Decimal
+		}{
+			{"buy", &summary.Buy},
+			{"sell", &summary.Sell},
+			{"net", &summary.Net},
+		}
+		for i, f := range fields {
+			*f.value, err = parsePrice(data[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing institutional summary %s: %w", f.name, err)
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
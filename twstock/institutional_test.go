@@ -0,0 +1,458 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	testTwseInstitutionalFields = `["證券代號", "證券名稱", "` + strings.Join(twseInstitutionalFields, `", "`) + `"]`
+	testTpexInstitutionalFields = `["` + strings.Join(tpexInstitutionalFields, `", "`) + `"]`
+	testTpexInstitutionalRow    = `"3374", "精材"` + strings.Repeat(`, "0"`, len(tpexInstitutionalFields)-2)
+)
+
+func TestInstitutionalService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("selectType"), "ALLBUT0999"; got != want {
+			t.Errorf("Request selectType = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"title": "111年08月22日 三大法人買賣超日報",
+			"fields": ["證券代號", "證券名稱", "外陸資買進股數(不含外資自營商)", "外陸資賣出股數(不含外資自營商)", "外陸資買賣超股數(不含外資自營商)", "外資自營商買進股數", "外資自營商賣出股數", "外資自營商買賣超股數", "投信買進股數", "投信賣出股數", "投信買賣超股數", "自營商買賣超股數", "自營商買進股數(自行買賣)", "自營商賣出股數(自行買賣)", "自營商買賣超股數(自行買賣)", "自營商買進股數(避險)", "自營商賣出股數(避險)", "自營商買賣超股數(避險)", "三大法人買賣超股數"],
+			"data": [
+				["2330  ", "台積電          ", "8,237,640", "21,352,110", "-13,114,470", "0", "0", "0", "36,000", "116,330", "-80,330", "-185,532", "54,000", "90,000", "-36,000", "215,468", "365,000", "-149,532", "-13,380,332"]
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	trades, err := client.Institutional.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Institutional.DownloadTwse returned error: %v", err)
+	}
+	want := map[string]InstitutionalTrade{
+		"2330": {
+			Date:                  date,
+			Code:                  "2330",
+			Name:                  "台積電",
+			ForeignBuy:            8237640,
+			ForeignSell:           21352110,
+			ForeignNet:            -13114470,
+			InvestmentTrustBuy:    36000,
+			InvestmentTrustSell:   116330,
+			InvestmentTrustNet:    -80330,
+			DealerNet:             -185532,
+			DealerProprietaryBuy:  54000,
+			DealerProprietarySell: 90000,
+			DealerProprietaryNet:  -36000,
+			DealerHedgeBuy:        215468,
+			DealerHedgeSell:       365000,
+			DealerHedgeNet:        -149532,
+			TotalNet:              -13380332,
+		},
+	}
+	if !cmp.Equal(trades, want) {
+		t.Errorf("Institutional.DownloadTwse returned %+v, want %+v", trades, want)
+	}
+}
+
+func TestInstitutionalService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Institutional.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestInstitutionalService_DownloadTwseBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["證券代號", "證券名稱"], "data": []}`)
+	})
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 證券代號,證券名稱")
+}
+
+func TestInstitutionalService_DownloadTwseEmptyRow(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseInstitutionalFields+`, "data": [[]]}`)
+	})
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade fields")
+}
+
+func TestInstitutionalService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseInstitutionalFields+`, "data": [["2330", "台積電", "1"]]}`)
+	})
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade fields")
+}
+
+func TestInstitutionalService_DownloadTwseBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testTwseInstitutionalFields+`, "data": [["2330", "台積電", "BAD", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0"]]}`)
+	})
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade 2330")
+}
+
+func TestInstitutionalService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Institutional.DownloadTwse(context.Background(), civil.Date{Year: 2017, Month: time.December, Day: 15})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2017-12-15")
+}
+
+func TestInstitutionalService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("type"), "Daily"; got != want {
+			t.Errorf("Request type = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"title": "三大法人買賣明細資訊",
+					"date": "111/08/22",
+					"fields": ["代號", "名稱", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "買進股數", "賣出股數", "買賣超股數", "三大法人買賣超股數合計"],
+					"data": [
+						["3374", "精材", "1,069,000", "1,226,286", "-157,286", "0", "0", "0", "1,069,000", "1,226,286", "-157,286", "2,000", "0", "2,000", "31,000", "61,000", "-30,000", "5,000", "6,000", "-1,000", "36,000", "67,000", "-31,000", "-186,286"]
+					],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	trades, err := client.Institutional.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Institutional.DownloadTpex returned error: %v", err)
+	}
+	want := map[string]InstitutionalTrade{
+		"3374": {
+			Date:                  date,
+			Code:                  "3374",
+			Name:                  "精材",
+			ForeignBuy:            1069000,
+			ForeignSell:           1226286,
+			ForeignNet:            -157286,
+			InvestmentTrustBuy:    2000,
+			InvestmentTrustNet:    2000,
+			DealerNet:             -31000,
+			DealerProprietaryBuy:  31000,
+			DealerProprietarySell: 61000,
+			DealerProprietaryNet:  -30000,
+			DealerHedgeBuy:        5000,
+			DealerHedgeSell:       6000,
+			DealerHedgeNet:        -1000,
+			TotalNet:              -186286,
+		},
+	}
+	if !cmp.Equal(trades, want) {
+		t.Errorf("Institutional.DownloadTpex returned %+v, want %+v", trades, want)
+	}
+}
+
+func TestInstitutionalService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("Institutional.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestInstitutionalService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Institutional.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestInstitutionalService_DownloadTpexBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade fields: 代號")
+}
+
+func TestInstitutionalService_DownloadTpexBadDate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"date": "BAD", "fields": `+testTpexInstitutionalFields+`, "data": [[`+testTpexInstitutionalRow+`]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote date")
+}
+
+func TestInstitutionalService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexInstitutionalFields+`, "data": [["3374", "精材"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade fields")
+}
+
+func TestInstitutionalService_DownloadTpexBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexInstitutionalFields+`, "data": [["3374", "精材", "BAD"`+strings.Repeat(`, "0"`, len(tpexInstitutionalFields)-3)+`]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional trade 3374")
+}
+
+func TestInstitutionalService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2018, Month: time.January, Day: 12})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2018-01-12")
+}
+
+func TestInstitutionalService_DownloadTpexExtraColumn(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// 多出來的欄位不影響解析
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexInstitutionalFields+`, "data": [[`+testTpexInstitutionalRow+`, "BAD"]], "totalCount": 1}]}`)
+	})
+
+	trades, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err != nil {
+		t.Fatalf("Institutional.DownloadTpex returned error: %v", err)
+	}
+	if _, ok := trades["3374"]; !ok {
+		t.Errorf("Institutional.DownloadTpex returned %+v, want 3374", trades)
+	}
+}
+
+func TestInstitutionalService_DownloadTwseSummary(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalSummaryPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("dayDate"), "20220822"; got != want {
+			t.Errorf("Request dayDate = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"title": "111年08月22日 三大法人買賣金額統計表",
+			"fields": ["單位名稱", "買進金額", "賣出金額", "買賣差額"],
+			"data": [
+				["投信", "3,183,446,650", "2,846,358,880", "337,087,770"],
+				["合計", "128,331,530,101", "157,103,297,338", "-28,771,767,237"]
+			]
+		}`)
+	})
+
+	summaries, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err != nil {
+		t.Fatalf("Institutional.DownloadTwseSummary returned error: %v", err)
+	}
+	want := []InstitutionalSummary{
+		{Name: "投信", Buy: decimal.NewFromInt(3183446650), Sell: decimal.NewFromInt(2846358880), Net: decimal.NewFromInt(337087770)},
+		{Name: "合計", Buy: decimal.NewFromInt(128331530101), Sell: decimal.NewFromInt(157103297338), Net: decimal.NewFromInt(-28771767237)},
+	}
+	if !cmp.Equal(summaries, want) {
+		t.Errorf("Institutional.DownloadTwseSummary returned %+v, want %+v", summaries, want)
+	}
+}
+
+func TestInstitutionalService_DownloadTwseSummaryErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalSummaryPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Institutional.DownloadTwseSummary returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestInstitutionalService_DownloadTwseSummaryBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalSummaryPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["單位名稱"], "data": []}`)
+	})
+
+	_, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwseSummary returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional summary fields: 單位名稱")
+}
+
+func TestInstitutionalService_DownloadTwseSummaryBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalSummaryPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["單位名稱", "買進金額", "賣出金額", "買賣差額"], "data": [["投信"]]}`)
+	})
+
+	_, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwseSummary returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional summary fields")
+}
+
+func TestInstitutionalService_DownloadTwseSummaryBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseInstitutionalSummaryPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["單位名稱", "買進金額", "賣出金額", "買賣差額"], "data": [["投信", "1", "BAD", "1"]]}`)
+	})
+
+	_, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwseSummary returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing institutional summary sell")
+}
+
+func TestInstitutionalService_DownloadTwseSummaryInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Institutional.DownloadTwseSummary(context.Background(), civil.Date{Year: 2017, Month: time.January, Day: 3})
+	if err == nil {
+		t.Fatal("Institutional.DownloadTwseSummary returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2017-01-03")
+}
+
+func TestInstitutionalService_DownloadTpexReorderedFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// 三大法人合計移到投信之前
+	fields := append([]string{}, tpexInstitutionalFields[:11]...)
+	fields = append(fields, tpexInstitutionalFields[23])
+	fields = append(fields, tpexInstitutionalFields[11:23]...)
+	mux.HandleFunc(tpexInstitutionalPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["`+strings.Join(fields, `", "`)+`"], "data": [["3374", "精材"`+strings.Repeat(`, "0"`, len(fields)-2)+`]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Institutional.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	testErrorContains(t, err, "failed parsing institutional trade fields")
+}
@@ -0,0 +1,336 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+)
+
+type MarginService struct {
+	client *Client
+}
+
+const (
+	// 上市融資融券餘額
+	twseMarginPath = "/rwd/zh/marginTrading/MI_MARGN"
+
+	// 上櫃融資融券餘額
+	tpexMarginPath = "/www/zh-tw/margin/balance"
+)
+
+// 個股融資融券餘額，單位為張（交易單位）
+type Margin struct {
+	Date civil.Date // 日期
+	Code string     // 股票代號
+	Name string     // 股票名稱
+
+	MarginBuy             int // 融資買進
+	MarginSell            int // 融資賣出
+	MarginRedemption      int // 現金償還
+	MarginPreviousBalance int // 融資前日餘額
+	MarginBalance         int // 融資今日餘額
+	MarginLimit           int // 融資限額
+
+	ShortSell            int // 融券賣出
+	ShortCover           int // 融券買進
+	ShortRedemption      int // 現券償還
+	ShortPreviousBalance int // 融券前日餘額
+	ShortBalance         int // 融券今日餘額
+	ShortLimit           int // 融券限額
+
+	Offset int    // 資券互抵
+	Note   string // 註記
+}
+
+type twseMarginOptions struct {
+	Response   string `url:"response"`
+	Date       string `url:"date"`
+	SelectType string `url:"selectType"`
+}
+
+// volumes returns pointers to the share fields in the order of the columns
+// of marginColumns.
+func (m *Margin) volumes() []*int {
+	return []*int{
+		&m.MarginBuy, &m.MarginSell, &m.MarginRedemption, &m.MarginPreviousBalance, &m.MarginBalance, &m.MarginLimit,
+		&m.ShortSell, &m.ShortCover, &m.ShortRedemption, &m.ShortPreviousBalance, &m.ShortBalance, &m.ShortLimit,
+		&m.Offset,
+	}
+}
+
+// marginColumns are the columns of a report in the order of Margin.volumes,
+// the note is optional in the data.
+type marginColumns struct {
+	volumes []int
+	note    int
+}
+
+var (
+	// 上市融資融券彙總的融資、融券欄位名稱相同，只能依位置解析
+	twseMarginColumns = marginColumns{
+		volumes: []int{2, 3, 4, 5, 6, 7, 9, 8, 10, 11, 12, 13, 14},
+		note:    15,
+	}
+
+	// 上櫃融資融券餘額的資屬證金及使用率欄位不解析
+	tpexMarginColumns = marginColumns{
+		volumes: []int{3, 4, 5, 2, 6, 9, 11, 12, 13, 10, 14, 17, 18},
+		note:    19,
+	}
+)
+
+func (*MarginService) parse(date civil.Date, data []string, columns marginColumns) (Margin, error) {
+	margin := Margin{Date: date}
+	if len(data) < 2 {
+		return margin, fmt.Errorf("failed parsing margin fields")
+	}
+	margin.Code = strings.TrimSpace(data[0])
+	margin.Name = strings.TrimSpace(data[1])
+	for i, v := range margin.volumes() {
+		if columns.volumes[i] >= len(data) {
+			return margin, fmt.Errorf("failed parsing margin fields")
+		}
+		volume, err := parseVolume(strings.TrimSpace(data[columns.volumes[i]]))
+		if err != nil {
+			return margin, fmt.Errorf("failed parsing margin %s: %w", margin.Code, err)
+		}
+		*v = volume
+	}
+	if columns.note < len(data) {
+		margin.Note = strings.TrimSpace(data[columns.note])
+	}
+	return margin, nil
+}
+
+// 融資或融券的買賣及餘額合計
+type MarginTotal struct {
+	Buy             int // 買進，融券為融券買進（回補）
+	Sell            int // 賣出，融券為融券賣出
+	Redemption      int // 現金（券）償還
+	PreviousBalance int // 前日餘額
+	Balance         int // 今日餘額
+}
+
+// 全市場信用交易統計
+type MarginSummary struct {
+	Date        civil.Date  // 日期
+	Margin      MarginTotal // 融資，單位為張（交易單位）
+	Short       MarginTotal // 融券，單位為張（交易單位）
+	MarginValue MarginTotal // 融資金額，單位為仟元，僅台灣證卷交易所提供
+}
+
+// 加總個股融資融券餘額，取得全市場的融資融券合計。
+// 個股的次一營業日限額及資券互抵無法加總，不包含在合計中
+func TotalMargin(margins map[string]Margin) MarginSummary {
+	var summary MarginSummary
+	for _, margin := range margins {
+		summary.Date = margin.Date
+		summary.Margin.Buy += margin.MarginBuy
+		summary.Margin.Sell += margin.MarginSell
+		summary.Margin.Redemption += margin.MarginRedemption
+		summary.Margin.PreviousBalance += margin.MarginPreviousBalance
+		summary.Margin.Balance += margin.MarginBalance
+		summary.Short.Buy += margin.ShortCover
+		summary.Short.Sell += margin.ShortSell
+		summary.Short.Redemption += margin.ShortRedemption
+		summary.Short.PreviousBalance += margin.ShortPreviousBalance
+		summary.Short.Balance += margin.ShortBalance
+	}
+	return summary
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *MarginService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所融資融券餘額最早到民國90年1月
+		return civil.Date{Year: 2001, Month: time.January, Day: 1}
+	}
+	// 證券櫃檯買賣中心融資融券餘額最早到民國96年1月
+	return civil.Date{Year: 2007, Month: time.January, Day: 1}
+}
+
+func (s *MarginService) downloadTwse(ctx context.Context, date civil.Date) (*twseResponse, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseMarginPath)
+	opts := twseMarginOptions{
+		Response:   "json",
+		Date:       fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		SelectType: "ALL",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	return resp, nil
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的融資融券餘額
+func (s *MarginService) DownloadTwse(ctx context.Context, date civil.Date) (map[string]Margin, error) {
+	resp, err := s.downloadTwse(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range resp.Tables {
+		fields := table.Fields
+		// 第一個表格為信用交易統計，第二個表格才是個股的融資融券彙總
+		if len(fields) < 15 ||
+			fields[0] != "代號" ||
+			fields[1] != "名稱" ||
+			fields[2] != "買進" ||
+			fields[3] != "賣出" ||
+			fields[4] != "現金償還" ||
+			fields[8] != "買進" ||
+			fields[9] != "賣出" ||
+			fields[10] != "現券償還" ||
+			fields[14] != "資券互抵" {
+			continue
+		}
+		margins := map[string]Margin{}
+		for _, data := range table.Data {
+			margin, err := s.parse(date, data, twseMarginColumns)
+			if err != nil {
+				return nil, err
+			}
+			margins[margin.Code] = margin
+		}
+		return margins, nil
+	}
+	return nil, fmt.Errorf("failed parsing margin fields: margin table not found")
+}
+
+// 信用交易統計的列名稱
+var twseMarginSummaryRows = []string{"融資(交易單位)", "融券(交易單位)", "融資金額(仟元)"}
+
+// 從台灣證卷交易所下載指定日期的信用交易統計
+func (s *MarginService) DownloadTwseSummary(ctx context.Context, date civil.Date) (MarginSummary, error) {
+	summary := MarginSummary{Date: date}
+	resp, err := s.downloadTwse(ctx, date)
+	if err != nil {
+		return summary, err
+	}
+	for _, table := range resp.Tables {
+		indexes, err := fieldIndexes(table.Fields, "項目", "買進", "賣出", "現金(券)償還", "前日餘額", "今日餘額")
+		if err != nil {
+			continue
+		}
+		totals := []*MarginTotal{&summary.Margin, &summary.Short, &summary.MarginValue}
+		found := 0
+		for _, data := range table.Data {
+			if len(data) <= indexes[0] {
+				continue
+			}
+			for i, name := range twseMarginSummaryRows {
+				if strings.TrimSpace(data[indexes[0]]) != name {
+					continue
+				}
+				if err := parseMarginTotal(data, indexes[1:], totals[i]); err != nil {
+					return summary, fmt.Errorf("failed parsing margin summary %s: %w", name, err)
+				}
+				found++
+			}
+		}
+		if found != len(twseMarginSummaryRows) {
+			return summary, fmt.Errorf("failed parsing margin summary rows")
+		}
+		return summary, nil
+	}
+	return summary, fmt.Errorf("failed parsing margin summary fields: summary table not found")
+}
+
+// parseMarginTotal parses the columns at indexes in the order of the fields
+// of MarginTotal.
+func parseMarginTotal(data []string, indexes []int, total *MarginTotal) error {
+	values := []*int{&total.Buy, &total.Sell, &total.Redemption, &total.PreviousBalance, &total.Balance}
+	for i, v := range values {
+		if indexes[i] >= len(data) {
+			return fmt.Errorf("failed parsing margin fields")
+		}
+		volume, err := parseVolume(strings.TrimSpace(data[indexes[i]]))
+		if err != nil {
+			return err
+		}
+		*v = volume
+	}
+	return nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的融資融券餘額
+func (s *MarginService) DownloadTpex(ctx context.Context, date civil.Date) (map[string]Margin, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexMarginPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	fields := make([]string, len(table.Fields))
+	for i, v := range table.Fields {
+		fields[i] = strings.TrimSpace(v)
+	}
+	if len(fields) < 19 ||
+		fields[0] != "代號" ||
+		fields[1] != "名稱" ||
+		fields[3] != "資買" ||
+		fields[4] != "資賣" ||
+		fields[5] != "現償" ||
+		fields[11] != "券賣" ||
+		fields[12] != "券買" ||
+		fields[13] != "券償" {
+		return nil, fmt.Errorf("failed parsing margin fields: %s", strings.Join(fields, ","))
+	}
+	date, err = tpexTableDate(date, table.Date)
+	if err != nil {
+		return nil, err
+	}
+	margins := map[string]Margin{}
+	for _, row := range table.Data {
+		margin, err := s.parse(date, tpexStrings(row), tpexMarginColumns)
+		if err != nil {
+			return nil, err
+		}
+		margins[margin.Code] = margin
+	}
+	return margins, nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的融資融券餘額，並加總成全市場的信用交易統計
+func (s *MarginService) DownloadTpexSummary(ctx context.Context, date civil.Date) (MarginSummary, error) {
+	margins, err := s.DownloadTpex(ctx, date)
+	if err != nil {
+		return MarginSummary{Date: date}, err
+	}
+	summary := TotalMargin(margins)
+	summary.Date = date
+	// 使用報表的日期
+	for _, margin := range margins {
+		summary.Date = margin.Date
+		break
+	}
+	return summary, nil
+}
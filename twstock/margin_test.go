@@ -0,0 +1,439 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testTwseMarginFields = `["代號", "名稱", "買進", "賣出", "現金償還", "前日餘額", "今日餘額", "次一營業日限額", "買進", "賣出", "現券償還", "前日餘額", "今日餘額", "次一營業日限額", "資券互抵"]`
+	testTpexMarginFields = `["代號", "名稱", "前資餘額(張)", "資買", "資賣", "現償", "資餘額", "資屬證金", "資使用率(%)", "資限額", "前券餘額(張)", "券賣", "券買", "券償", "券餘額", "券屬證金", "券使用率(%)", "券限額", "資券相抵(張)"]`
+)
+
+func TestMarginService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("selectType"), "ALL"; got != want {
+			t.Errorf("Request selectType = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"tables": [
+				{
+					"title": "111年08月22日 信用交易統計",
+					"fields": ["項目", "買進", "賣出", "現金(券)償還", "前日餘額", "今日餘額"],
+					"data": [["融資(交易單位)", "275,612", "300,401", "6,238", "7,391,154", "7,360,127"]]
+				},
+				{
+					"title": "111年08月22日 融資融券彙總 (全部)",
+					"fields": ["代號", "名稱", "買進", "賣出", "現金償還", "前日餘額", "今日餘額", "次一營業日限額", "買進", "賣出", "現券償還", "前日餘額", "今日餘額", "次一營業日限額", "資券互抵", "註記"],
+					"data": [
+						["2330", "台積電", "1,561", "1,921", "41", "27,474", "27,073", "6,483,073", "24", "312", "3", "3,024", "3,309", "6,483,073", "13", ""],
+						["1101", "台泥", "573", "510", "10", "11,251", "11,304", "1,754,470", "1", "26", "0", "1,063", "1,088", "1,754,470", "2", "X"]
+					]
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	margins, err := client.Margin.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Margin.DownloadTwse returned error: %v", err)
+	}
+	want := map[string]Margin{
+		"2330": {
+			Date:                  date,
+			Code:                  "2330",
+			Name:                  "台積電",
+			MarginBuy:             1561,
+			MarginSell:            1921,
+			MarginRedemption:      41,
+			MarginPreviousBalance: 27474,
+			MarginBalance:         27073,
+			MarginLimit:           6483073,
+			ShortSell:             312,
+			ShortCover:            24,
+			ShortRedemption:       3,
+			ShortPreviousBalance:  3024,
+			ShortBalance:          3309,
+			ShortLimit:            6483073,
+			Offset:                13,
+		},
+		"1101": {
+			Date:                  date,
+			Code:                  "1101",
+			Name:                  "台泥",
+			MarginBuy:             573,
+			MarginSell:            510,
+			MarginRedemption:      10,
+			MarginPreviousBalance: 11251,
+			MarginBalance:         11304,
+			MarginLimit:           1754470,
+			ShortSell:             26,
+			ShortCover:            1,
+			ShortPreviousBalance:  1063,
+			ShortBalance:          1088,
+			ShortLimit:            1754470,
+			Offset:                2,
+			Note:                  "X",
+		},
+	}
+	if !cmp.Equal(margins, want) {
+		t.Errorf("Margin.DownloadTwse returned %+v, want %+v", margins, want)
+	}
+
+	total := TotalMargin(margins)
+	wantTotal := MarginSummary{
+		Date:   date,
+		Margin: MarginTotal{Buy: 2134, Sell: 2431, Redemption: 51, PreviousBalance: 38725, Balance: 38377},
+		Short:  MarginTotal{Buy: 25, Sell: 338, Redemption: 3, PreviousBalance: 4087, Balance: 4397},
+	}
+	if !cmp.Equal(total, wantTotal) {
+		t.Errorf("TotalMargin returned %+v, want %+v", total, wantTotal)
+	}
+}
+
+func TestMarginService_DownloadTwseSummary(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"tables": [
+				{
+					"title": "111年08月22日 信用交易統計",
+					"fields": ["項目", "買進", "賣出", "現金(券)償還", "前日餘額", "今日餘額"],
+					"data": [
+						["融資(交易單位)", "275,612", "300,401", "6,238", "7,391,154", "7,360,127"],
+						["融券(交易單位)", "24,361", "30,285", "1,019", "542,817", "547,722"],
+						["融資金額(仟元)", "9,339,512", "10,185,452", "212,390", "262,364,717", "261,306,387"]
+					]
+				},
+				{
+					"title": "111年08月22日 融資融券彙總 (全部)",
+					"fields": ["代號", "名稱", "買進", "賣出", "現金償還", "前日餘額", "今日餘額", "次一營業日限額", "買進", "賣出", "現券償還", "前日餘額", "今日餘額", "次一營業日限額", "資券互抵", "註記"],
+					"data": []
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	summary, err := client.Margin.DownloadTwseSummary(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Margin.DownloadTwseSummary returned error: %v", err)
+	}
+	want := MarginSummary{
+		Date:        date,
+		Margin:      MarginTotal{Buy: 275612, Sell: 300401, Redemption: 6238, PreviousBalance: 7391154, Balance: 7360127},
+		Short:       MarginTotal{Buy: 24361, Sell: 30285, Redemption: 1019, PreviousBalance: 542817, Balance: 547722},
+		MarginValue: MarginTotal{Buy: 9339512, Sell: 10185452, Redemption: 212390, PreviousBalance: 262364717, Balance: 261306387},
+	}
+	if !cmp.Equal(summary, want) {
+		t.Errorf("Margin.DownloadTwseSummary returned %+v, want %+v", summary, want)
+	}
+}
+
+func TestMarginService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Margin.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestMarginService_DownloadTwseNoMarginTable(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["項目", "買進"], "data": []}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwse returned nil; expected error")
+	}
+	if errors.Is(err, ErrNoData) {
+		t.Errorf("Margin.DownloadTwse returned %v, want fields error", err)
+	}
+	testErrorContains(t, err, "failed parsing margin fields: margin table not found")
+}
+
+func TestMarginService_DownloadTwseEmptyRow(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testTwseMarginFields+`, "data": [["2330"]]}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin fields")
+}
+
+func TestMarginService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testTwseMarginFields+`, "data": [["2330", "台積電", "1"]]}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin fields")
+}
+
+func TestMarginService_DownloadTwseBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": `+testTwseMarginFields+`, "data": [["2330", "台積電", "BAD", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0"]]}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin 2330")
+}
+
+func TestMarginService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Margin.DownloadTwse(context.Background(), civil.Date{Year: 2000, Month: time.December, Day: 29})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2000-12-29")
+}
+
+func TestMarginService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"title": "上櫃股票融資融券餘額",
+					"date": "20220822",
+					"fields": ["代號", "名稱", "前資餘額(張)", "資買", "資賣", "現償", "資餘額", "資屬證金", "資使用率(%)", "資限額", "前券餘額(張)", "券賣", "券買", "券償", "券餘額", "券屬證金", "券使用率(%)", "券限額", "資券相抵(張)", "備註"],
+					"data": [
+						["3374", "精材", "2,016", "143", "263", "2", "1,894", "0", "2.78", "67,920", "160", "31", "17", "0", "174", "0", "0.25", "67,920", "5", ""]
+					],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	margins, err := client.Margin.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Margin.DownloadTpex returned error: %v", err)
+	}
+	want := map[string]Margin{
+		"3374": {
+			Date:                  date,
+			Code:                  "3374",
+			Name:                  "精材",
+			MarginBuy:             143,
+			MarginSell:            263,
+			MarginRedemption:      2,
+			MarginPreviousBalance: 2016,
+			MarginBalance:         1894,
+			MarginLimit:           67920,
+			ShortSell:             31,
+			ShortCover:            17,
+			ShortPreviousBalance:  160,
+			ShortBalance:          174,
+			ShortLimit:            67920,
+			Offset:                5,
+		},
+	}
+	if !cmp.Equal(margins, want) {
+		t.Errorf("Margin.DownloadTpex returned %+v, want %+v", margins, want)
+	}
+}
+
+func TestMarginService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("Margin.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestMarginService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Margin.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestMarginService_DownloadTpexBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin fields: 代號")
+}
+
+func TestMarginService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexMarginFields+`, "data": [["3374", "精材", "1"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin fields")
+}
+
+func TestMarginService_DownloadTpexBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testTpexMarginFields+`, "data": [["3374", "精材", "BAD", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing margin 3374")
+}
+
+func TestMarginService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Margin.DownloadTpex(context.Background(), civil.Date{Year: 2006, Month: time.December, Day: 29})
+	if err == nil {
+		t.Fatal("Margin.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2006-12-29")
+}
+
+func TestMarginService_DownloadTwseSummaryNoTable(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["代號", "名稱"], "data": []}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Margin.DownloadTwseSummary returned nil; expected error")
+	}
+	if errors.Is(err, ErrNoData) {
+		t.Errorf("Margin.DownloadTwseSummary returned %v, want fields error", err)
+	}
+	testErrorContains(t, err, "failed parsing margin summary fields: summary table not found")
+}
+
+func TestMarginService_DownloadTwseSummaryMissingRow(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "tables": [{"fields": ["項目", "買進", "賣出", "現金(券)償還", "前日餘額", "今日餘額"], "data": [["融資(交易單位)", "1", "1", "1", "1", "1"]]}]}`)
+	})
+
+	_, err := client.Margin.DownloadTwseSummary(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	testErrorContains(t, err, "failed parsing margin summary rows")
+}
+
+func TestMarginService_DownloadTpexSummary(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexMarginPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號", "名稱", "前資餘額(張)", "資買", "資賣", "現償", "資餘額", "資屬證金", "資使用率(%)", "資限額", "前券餘額(張)", "券賣", "券買", "券償", "券餘額", "券屬證金", "券使用率(%)", "券限額", "資券相抵(張)", "備註"], "data": [
+			["3374", "精材", "2,016", "143", "263", "2", "1,894", "0", "2.78", "67,920", "160", "31", "17", "0", "174", "0", "0.25", "67,920", "5", ""],
+			["6488", "環球晶", "4,000", "100", "200", "0", "3,900", "0", "1.00", "108,000", "100", "10", "20", "0", "90", "0", "0.01", "108,000", "1", ""]
+		], "totalCount": 2}]}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	summary, err := client.Margin.DownloadTpexSummary(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Margin.DownloadTpexSummary returned error: %v", err)
+	}
+	want := MarginSummary{
+		Date:   date,
+		Margin: MarginTotal{Buy: 243, Sell: 463, Redemption: 2, PreviousBalance: 6016, Balance: 5794},
+		Short:  MarginTotal{Buy: 37, Sell: 41, PreviousBalance: 260, Balance: 264},
+	}
+	if !cmp.Equal(summary, want) {
+		t.Errorf("Margin.DownloadTpexSummary returned %+v, want %+v", summary, want)
+	}
+}
@@ -1,6 +1,7 @@
 package twstock
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -166,6 +167,11 @@ func (s *MarketDataService) MinimumDate(m Market) civil.Date {
 
 // 從台灣證卷交易所下載盤後每日市場成交資訊
 func (s *MarketDataService) DownloadTwse(year int, month time.Month) ([]MarketData, error) {
+	return s.DownloadTwseWithContext(context.Background(), year, month)
+}
+
+// 從台灣證卷交易所下載盤後每日市場成交資訊，可透過 ctx 取消請求
+func (s *MarketDataService) DownloadTwseWithContext(ctx context.Context, year int, month time.Month) ([]MarketData, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	if date.Before(s.MinimumDate(TWSE)) {
 		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
@@ -176,17 +182,14 @@ func (s *MarketDataService) DownloadTwse(year int, month time.Month) ([]MarketDa
 		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
 	}
 	url, _ = addOptions(url, opts)
-	req, _ := s.client.NewRequest("GET", url.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	resp := &twseResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
 		return nil, err
 	}
 	if resp.Stat != "OK" {
-		if isDateOutOfRangeStat(resp.Stat) {
-			return nil, ErrDateOutOffRange
-		}
-		return nil, fmt.Errorf("invalid state: %s", resp.Stat)
+		return nil, newStatError(url, resp.Stat)
 	}
 	if len(resp.Fields) != 6 ||
 		resp.Fields[0] != "日期" ||
@@ -210,6 +213,11 @@ func (s *MarketDataService) DownloadTwse(year int, month time.Month) ([]MarketDa
 
 // 從證券櫃檯買賣中心下載盤後每日市場成交資訊
 func (s *MarketDataService) DownloadTpex(year int, month time.Month) ([]MarketData, error) {
+	return s.DownloadTpexWithContext(context.Background(), year, month)
+}
+
+// 從證券櫃檯買賣中心下載盤後每日市場成交資訊，可透過 ctx 取消請求
+func (s *MarketDataService) DownloadTpexWithContext(ctx context.Context, year int, month time.Month) ([]MarketData, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	if date.Before(s.MinimumDate(TPEx)) {
 		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
@@ -220,12 +228,15 @@ func (s *MarketDataService) DownloadTpex(year int, month time.Month) ([]MarketDa
 		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
 	}
 	url, _ = addOptions(url, opts)
-	req, _ := s.client.NewRequest("GET", url.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	resp := &tpexResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
 		return nil, err
 	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
 	if len(resp.Tables) != 1 || resp.Tables[0].TotalCount == 0 {
 		return nil, ErrNoData
 	}
@@ -253,6 +264,11 @@ func (s *MarketDataService) DownloadTpex(year int, month time.Month) ([]MarketDa
 
 // 從台灣證卷交易所下載發行量加權股價指數歷史資料，最早到民國88年1月
 func (s *MarketDataService) DownloadTAIEX(year int, month time.Month) ([]TAIEXIndex, error) {
+	return s.DownloadTAIEXWithContext(context.Background(), year, month)
+}
+
+// 從台灣證卷交易所下載發行量加權股價指數歷史資料，最早到民國88年1月，可透過 ctx 取消請求
+func (s *MarketDataService) DownloadTAIEXWithContext(ctx context.Context, year int, month time.Month) ([]TAIEXIndex, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	minimumDate := civil.Date{Year: 1999, Month: time.January, Day: 1}
 	if date.Before(minimumDate) {
@@ -264,17 +280,14 @@ func (s *MarketDataService) DownloadTAIEX(year int, month time.Month) ([]TAIEXIn
 		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
 	}
 	u, _ = addOptions(u, opts)
-	req, _ := s.client.NewRequest("GET", u.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	resp := &twseResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
 		return nil, err
 	}
 	if resp.Stat != "OK" {
-		if isDateOutOfRangeStat(resp.Stat) {
-			return nil, ErrDateOutOffRange
-		}
-		return nil, fmt.Errorf("invalid state: %s", resp.Stat)
+		return nil, newStatError(u, resp.Stat)
 	}
 	if len(resp.Fields) != 5 ||
 		resp.Fields[0] != "日期" ||
@@ -297,6 +310,11 @@ func (s *MarketDataService) DownloadTAIEX(year int, month time.Month) ([]TAIEXIn
 
 // 從證券櫃檯買賣中心下載櫃買指數歷史資料，最早到民國88年9月
 func (s *MarketDataService) DownloadTPExIndex(year int, month time.Month) ([]TPExIndex, error) {
+	return s.DownloadTPExIndexWithContext(context.Background(), year, month)
+}
+
+// 從證券櫃檯買賣中心下載櫃買指數歷史資料，最早到民國88年9月，可透過 ctx 取消請求
+func (s *MarketDataService) DownloadTPExIndexWithContext(ctx context.Context, year int, month time.Month) ([]TPExIndex, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	minimumDate := civil.Date{Year: 1999, Month: time.September, Day: 1}
 	if date.Before(minimumDate) {
@@ -307,7 +325,7 @@ func (s *MarketDataService) DownloadTPExIndex(year int, month time.Month) ([]TPE
 		"response": {"json"},
 		"date":     {fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day)},
 	}.Encode()
-	req, _ := s.client.NewRequest("POST", u.String(), body)
+	req, _ := s.client.NewRequestWithContext(ctx, "POST", u.String(), body)
 	resp := &tpexResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
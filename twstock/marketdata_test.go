@@ -1,6 +1,7 @@
 package twstock
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -151,6 +152,33 @@ func TestMarketDataService_DownloadTwseError(t *testing.T) {
 	}
 }
 
+func TestMarketDataService_DownloadWithContextCanceled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.MarketData.DownloadTwseWithContext(ctx, 2022, 8)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MarketData.DownloadTwseWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = client.MarketData.DownloadTpexWithContext(ctx, 2022, 8)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MarketData.DownloadTpexWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = client.MarketData.DownloadTAIEXWithContext(ctx, 2022, 8)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MarketData.DownloadTAIEXWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = client.MarketData.DownloadTPExIndexWithContext(ctx, 2022, 8)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MarketData.DownloadTPExIndexWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestMarketDataService_DownloadTwseBadStat(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -1,9 +1,12 @@
 package twstock
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,12 +20,17 @@ type QuoteService struct {
 }
 
 type Quote struct {
-	Date   civil.Date      // 本筆資料所屬日期
-	Open   decimal.Decimal // 開盤價
-	High   decimal.Decimal // 最高價
-	Low    decimal.Decimal // 最低價
-	Close  decimal.Decimal // 收盤價
-	Volume int             // 成交量
+	Date         civil.Date      // 本筆資料所屬日期
+	Open         decimal.Decimal // 開盤價
+	High         decimal.Decimal // 最高價
+	Low          decimal.Decimal // 最低價
+	Close        decimal.Decimal // 收盤價
+	Volume       int             // 成交量
+	TradeValue   decimal.Decimal // 成交金額
+	Change       decimal.Decimal // 漲跌價差
+	Transactions int             // 成交筆數
+	Note         string          // 註記，僅台灣證卷交易所提供
+	Suspended    bool            // 暫停交易，此時只有日期、成交量、成交金額及成交筆數
 }
 
 const (
@@ -34,6 +42,9 @@ const (
 
 	// 個股即時交易行情
 	realtimeQuotesPath = "/stock/api/getStockInfo.jsp"
+
+	// 盤中零股即時交易行情
+	realtimeOddLotQuotesPath = "/stock/api/getOddInfo.jsp"
 )
 
 type twseOptions struct {
@@ -42,30 +53,33 @@ type twseOptions struct {
 	Code     string `url:"stockNo,omitempty"`
 }
 
-type twseResponse struct {
-	Stat   string     `json:"stat"`
-	Date   string     `json:"date"`
+type twseTable struct {
 	Title  string     `json:"title"`
 	Fields []string   `json:"fields"`
 	Data   [][]string `json:"data"`
 	Notes  []string   `json:"notes"`
 }
 
+type twseResponse struct {
+	Stat   string      `json:"stat"`
+	Date   string      `json:"date"`
+	Title  string      `json:"title"`
+	Fields []string    `json:"fields"`
+	Data   [][]string  `json:"data"`
+	Notes  []string    `json:"notes"`
+	Tables []twseTable `json:"tables"`
+}
+
 var (
 	errSuspendedTrading = errors.New("parse: suspended trading")
 
-	// 當查詢不到個股日成交資訊丟出此錯誤
+	// 當查詢不到個股日成交資訊丟出此錯誤，網站回傳的訊息可透過 *StatError 取得
 	ErrNoData = errors.New("no data found")
 
-	// 當查詢日期超出限制的時候丟出此錯誤
+	// 當查詢日期超出限制的時候丟出此錯誤，網站回傳的訊息可透過 *StatError 取得
 	ErrDateOutOffRange = errors.New("date out of range")
 )
 
-func isDateOutOfRangeStat(stat string) bool {
-	return stat == "查詢日期大於今日，請重新查詢!" ||
-		strings.HasPrefix(stat, "查詢日期小於")
-}
-
 func parseWesternDate(s string) (civil.Date, error) {
 	var date civil.Date
 	rawDate := strings.Split(strings.TrimSpace(s), "/")
@@ -91,9 +105,12 @@ func parseWesternDate(s string) (civil.Date, error) {
 	return date, nil
 }
 
+// 部分報表的日期格式為 111年08月01日，統一轉成 111/08/01
+var chineseDateReplacer = strings.NewReplacer("年", "/", "月", "/", "日", "")
+
 func parseDate(s string) (civil.Date, error) {
 	var date civil.Date
-	rawDate := strings.Split(strings.TrimSpace(s), "/")
+	rawDate := strings.Split(chineseDateReplacer.Replace(strings.TrimSpace(s)), "/")
 	if len(rawDate) != 3 {
 		return date, fmt.Errorf("failed parsing quote date: %s", s)
 	}
@@ -118,13 +135,28 @@ func parseDate(s string) (civil.Date, error) {
 	return date, nil
 }
 
+// 直接將去除千分位的字串轉成 decimal.Decimal，避免經過 float64 損失精度
 func parsePrice(s string) (decimal.Decimal, error) {
-	var v decimal.Decimal
-	f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
-	if err != nil {
-		return v, err
+	return decimal.NewFromString(strings.ReplaceAll(strings.TrimSpace(s), ",", ""))
+}
+
+var (
+	// 部分網站的漲跌欄位會以 HTML 標記正負號
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+	// 除權息等不比價的日子漲跌欄位會加上 X 前綴或註記
+	changeMarkerReplacer = strings.NewReplacer("除權息", "", "除權", "", "除息", "", "X", "", "x", "")
+)
+
+// 解析漲跌價差，只有註記而沒有數值時視為 0
+func parseChange(s string) (decimal.Decimal, error) {
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = changeMarkerReplacer.Replace(s)
+	s = strings.Join(strings.Fields(s), "")
+	if s == "" {
+		return decimal.Zero, nil
 	}
-	return decimal.NewFromFloat(f), nil
+	return parsePrice(s)
 }
 
 func parseVolume(s string) (int, error) {
@@ -135,22 +167,71 @@ func parseVolume(s string) (int, error) {
 	return v, nil
 }
 
+// fieldIndexes returns the column index of each name in fields, surrounding
+// whitespace in the header is ignored.
+func fieldIndexes(fields []string, names ...string) ([]int, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = -1
+		for j, field := range fields {
+			if strings.TrimSpace(field) == name {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("failed parsing fields: %s", strings.Join(fields, ","))
+		}
+	}
+	return indexes, nil
+}
+
+// tpexStrings converts a row of TPEx data to strings.
+func tpexStrings(row []StringOrNumber) []string {
+	data := make([]string, len(row))
+	for i, v := range row {
+		data[i] = string(v)
+	}
+	return data
+}
+
+// 暫停交易的日子會回傳 errSuspendedTrading 以及只有日期、成交量等欄位的 Quote
 func (*QuoteService) parse(data []string) (Quote, error) {
 	var quote Quote
-	if len(data) < 7 {
+	if len(data) < 9 {
 		return quote, fmt.Errorf("failed parsing quote data")
 	}
+	date, err := parseDate(data[0])
+	if err != nil {
+		return quote, err
+	}
+	volume, err := parseVolume(data[1])
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote volume: %w", err)
+	}
+	tradeValue, err := parsePrice(data[2])
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote trade value: %w", err)
+	}
+	transactions, err := parseVolume(data[8])
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote transactions: %w", err)
+	}
+	quote.Date = date
+	quote.Volume = volume
+	quote.TradeValue = tradeValue
+	quote.Transactions = transactions
+	if len(data) > 9 {
+		quote.Note = strings.TrimSpace(data[9])
+	}
 	// 暫停交易
 	if data[3] == "--" ||
 		data[4] == "--" ||
 		data[5] == "--" ||
 		data[6] == "--" {
+		quote.Suspended = true
 		return quote, errSuspendedTrading
 	}
-	date, err := parseDate(data[0])
-	if err != nil {
-		return quote, err
-	}
 	open, err := parsePrice(data[3])
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote open: %w", err)
@@ -167,16 +248,15 @@ func (*QuoteService) parse(data []string) (Quote, error) {
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote close: %w", err)
 	}
-	volume, err := parseVolume(data[1])
+	change, err := parseChange(data[7])
 	if err != nil {
-		return quote, fmt.Errorf("failed parsing quote volume: %w", err)
+		return quote, fmt.Errorf("failed parsing quote change: %w", err)
 	}
-	quote.Date = date
 	quote.Open = open
 	quote.High = high
 	quote.Low = low
 	quote.Close = close
-	quote.Volume = volume
+	quote.Change = change
 	return quote, nil
 }
 
@@ -192,6 +272,15 @@ func (s *QuoteService) MinimumDate(m Market) civil.Date {
 
 // 從台灣證卷交易所下載盤後個股日成交資訊
 func (s *QuoteService) DownloadTwse(code string, year int, month time.Month) ([]Quote, error) {
+	return s.DownloadTwseWithContext(context.Background(), code, year, month)
+}
+
+// 從台灣證卷交易所下載盤後個股日成交資訊，可透過 ctx 取消請求
+func (s *QuoteService) DownloadTwseWithContext(ctx context.Context, code string, year int, month time.Month) ([]Quote, error) {
+	return s.downloadTwse(ctx, code, year, month, false)
+}
+
+func (s *QuoteService) downloadTwse(ctx context.Context, code string, year int, month time.Month, withSuspended bool) ([]Quote, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	if date.Before(s.MinimumDate(TWSE)) {
 		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
@@ -203,20 +292,14 @@ func (s *QuoteService) DownloadTwse(code string, year int, month time.Month) ([]
 		Code:     code,
 	}
 	url, _ = addOptions(url, opts)
-	req, _ := s.client.NewRequest("GET", url.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	resp := &twseResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
 		return nil, err
 	}
 	if resp.Stat != "OK" {
-		if resp.Stat == "很抱歉，沒有符合條件的資料!" {
-			return nil, ErrNoData
-		}
-		if isDateOutOfRangeStat(resp.Stat) {
-			return nil, ErrDateOutOffRange
-		}
-		return nil, fmt.Errorf("invalid state: %s", resp.Stat)
+		return nil, newStatError(url, resp.Stat)
 	}
 	if len(resp.Fields) != 10 ||
 		resp.Fields[0] != "日期" ||
@@ -234,10 +317,11 @@ func (s *QuoteService) DownloadTwse(code string, year int, month time.Month) ([]
 	quotes := []Quote{}
 	for _, data := range resp.Data {
 		quote, err := s.parse(data)
-		if err != nil {
-			if errors.Is(err, errSuspendedTrading) {
+		if errors.Is(err, errSuspendedTrading) {
+			if !withSuspended {
 				continue
 			}
+		} else if err != nil {
 			return nil, err
 		}
 		quotes = append(quotes, quote)
@@ -285,8 +369,38 @@ type tpexResponse struct {
 	} `json:"tables"`
 }
 
+// 證券櫃檯買賣中心的 stat 為小寫 ok，部分 API 不回傳 stat
+func isTpexStatOK(stat string) bool {
+	return stat == "" || strings.EqualFold(stat, "ok")
+}
+
+// 證券櫃檯買賣中心報表的日期為民國年或西元年 yyyymmdd，報表沒有日期時使用查詢的日期
+func tpexTableDate(date civil.Date, s string) (civil.Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return date, nil
+	}
+	if len(s) == 8 && !strings.Contains(s, "/") {
+		t, err := time.Parse("20060102", s)
+		if err != nil {
+			return date, fmt.Errorf("failed parsing quote date: %s", s)
+		}
+		return civil.DateOf(t), nil
+	}
+	return parseDate(s)
+}
+
 // 從證券櫃檯買賣中心下載盤後個股日成交資訊
 func (s *QuoteService) DownloadTpex(code string, year int, month time.Month) ([]Quote, error) {
+	return s.DownloadTpexWithContext(context.Background(), code, year, month)
+}
+
+// 從證券櫃檯買賣中心下載盤後個股日成交資訊，可透過 ctx 取消請求
+func (s *QuoteService) DownloadTpexWithContext(ctx context.Context, code string, year int, month time.Month) ([]Quote, error) {
+	return s.downloadTpex(ctx, code, year, month, false)
+}
+
+func (s *QuoteService) downloadTpex(ctx context.Context, code string, year int, month time.Month, withSuspended bool) ([]Quote, error) {
 	date := civil.Date{Year: year, Month: month, Day: 1}
 	if date.Before(s.MinimumDate(TPEx)) {
 		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
@@ -298,12 +412,15 @@ func (s *QuoteService) DownloadTpex(code string, year int, month time.Month) ([]
 		Code:     code,
 	}
 	url, _ = addOptions(url, opts)
-	req, _ := s.client.NewRequest("GET", url.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	resp := &tpexResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
 		return nil, err
 	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
 	if resp.Code != code {
 		return nil, fmt.Errorf("invalid tpex code returned %s, want %s", resp.Code, code)
 	}
@@ -323,14 +440,17 @@ func (s *QuoteService) DownloadTpex(code string, year int, month time.Month) ([]
 			stringData[i] = string(v)
 		}
 		quote, err := s.parse(stringData)
-		if err != nil {
-			if errors.Is(err, errSuspendedTrading) {
+		if errors.Is(err, errSuspendedTrading) {
+			if !withSuspended {
 				continue
 			}
+		} else if err != nil {
 			return nil, err
 		}
 		// 成交仟股
 		quote.Volume *= 1000
+		// 成交仟元
+		quote.TradeValue = quote.TradeValue.Mul(decimal.NewFromInt(1000))
 		quotes = append(quotes, quote)
 	}
 	return quotes, nil
@@ -338,13 +458,18 @@ func (s *QuoteService) DownloadTpex(code string, year int, month time.Month) ([]
 
 // 從台灣證卷交易所或證券櫃檯買賣中心下載盤後個股日成交資訊
 func (s *QuoteService) Download(code string, year int, month time.Month) ([]Quote, error) {
+	return s.DownloadWithContext(context.Background(), code, year, month)
+}
+
+// 從台灣證卷交易所或證券櫃檯買賣中心下載盤後個股日成交資訊，可透過 ctx 取消請求
+func (s *QuoteService) DownloadWithContext(ctx context.Context, code string, year int, month time.Month) ([]Quote, error) {
 	//nolint:typecheck
 	if security, ok := Securities[code]; ok {
 		switch security.Market {
 		case TWSE:
-			return s.DownloadTwse(code, year, month)
+			return s.DownloadTwseWithContext(ctx, code, year, month)
 		case TPEx:
-			return s.DownloadTpex(code, year, month)
+			return s.DownloadTpexWithContext(ctx, code, year, month)
 		default:
 			return nil, fmt.Errorf("invalid market: %s", security.Market)
 		}
@@ -352,6 +477,68 @@ func (s *QuoteService) Download(code string, year int, month time.Month) ([]Quot
 	return nil, fmt.Errorf("invalid code: %s", code)
 }
 
+// 從台灣證卷交易所或證券櫃檯買賣中心下載盤後個股日成交資訊，包含 Suspended 為 true 的暫停交易日，
+// 方便與沒有資料的休市日區分
+func (s *QuoteService) DownloadWithSuspended(ctx context.Context, code string, year int, month time.Month) ([]Quote, error) {
+	//nolint:typecheck
+	if security, ok := Securities[code]; ok {
+		switch security.Market {
+		case TWSE:
+			return s.downloadTwse(ctx, code, year, month, true)
+		case TPEx:
+			return s.downloadTpex(ctx, code, year, month, true)
+		default:
+			return nil, fmt.Errorf("invalid market: %s", security.Market)
+		}
+	}
+	return nil, fmt.Errorf("invalid code: %s", code)
+}
+
+// 從台灣證卷交易所或證券櫃檯買賣中心下載 from 到 to（包含）之間的盤後個股日成交資訊，
+// 早於 MinimumDate 及晚於今天（臺北時間）的日期會被略過，沒有資料的月份不視為錯誤
+func (s *QuoteService) DownloadRange(ctx context.Context, code string, from, to civil.Date) ([]Quote, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: %s - %s", from, to)
+	}
+	//nolint:typecheck
+	security, ok := Securities[code]
+	if !ok {
+		return nil, fmt.Errorf("invalid code: %s", code)
+	}
+	if security.Market != TWSE && security.Market != TPEx {
+		return nil, fmt.Errorf("invalid market: %s", security.Market)
+	}
+	if minimumDate := s.MinimumDate(security.Market); from.Before(minimumDate) {
+		from = minimumDate
+	}
+	if now := today(); to.After(now) {
+		to = now
+	}
+
+	quotes := []Quote{}
+	seen := map[civil.Date]bool{}
+	for year, month := from.Year, from.Month; !to.Before(civil.Date{Year: year, Month: month, Day: 1}); {
+		result, err := s.DownloadWithContext(ctx, code, year, month)
+		if err != nil && !errors.Is(err, ErrNoData) && !errors.Is(err, ErrDateOutOffRange) {
+			return nil, err
+		}
+		for _, quote := range result {
+			if quote.Date.Before(from) || quote.Date.After(to) || seen[quote.Date] {
+				continue
+			}
+			seen[quote.Date] = true
+			quotes = append(quotes, quote)
+		}
+		if month == time.December {
+			year, month = year+1, time.January
+		} else {
+			month++
+		}
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].Date.Before(quotes[j].Date) })
+	return quotes, nil
+}
+
 type BidAsk struct {
 	Price  decimal.Decimal // 價格
 	Volume int             // 數量
@@ -362,19 +549,90 @@ type RealtimeQuote struct {
 	Code     string          // 股票代號
 	Name     string          // 簡稱
 	FullName string          // 全名
-	Price    decimal.Decimal // 最新一筆成交價
-	Open     decimal.Decimal // 開盤價
-	High     decimal.Decimal // 最高價
-	Low      decimal.Decimal // 最低價
+	Price    decimal.Decimal // 最新一筆成交價，尚未成交時為最佳委買價或最佳委賣價
+	Open     decimal.Decimal // 開盤價，尚未成交時為 0
+	High     decimal.Decimal // 最高價，尚未成交時為 0
+	Low      decimal.Decimal // 最低價，尚未成交時為 0
 	Volume   int             // 總成交量
 	Bids     []BidAsk        // 最佳五檔委買資料
 	Asks     []BidAsk        // 最佳五檔委賣資料
+	HasTrade bool            // 是否有最新一筆成交價
+
+	PreviousClose   decimal.Decimal // 昨收價（漲跌參考價）
+	LimitUp         decimal.Decimal // 漲停價
+	LimitDown       decimal.Decimal // 跌停價
+	LastTradeVolume int             // 最新一筆成交量
+	Exchange        Market          // 市場別
+	Change          decimal.Decimal // 漲跌，尚未成交時為 0
+	ChangePercent   decimal.Decimal // 漲跌幅（%），四捨五入到小數第二位
+	OddLot          bool            // 是否為盤中零股行情，成交量及委買委賣量的單位為股
+	Trial           bool            // 是否為試算撮合的模擬價格，並非實際成交
+	Session         Session         // 交易時段
+}
+
+// 交易時段
+type Session string
+
+const (
+	SessionUnknown        Session = ""                // 無法判斷
+	SessionPreOpen        Session = "pre-open"        // 開盤前試撮
+	SessionContinuous     Session = "continuous"      // 盤中逐筆交易，盤中的試撮仍屬於此時段
+	SessionClosingAuction Session = "closing-auction" // 收盤前集合競價
+	SessionClosed         Session = "closed"          // 收盤
+)
+
+// 臺灣證券交易所的交易時段，以臺北時間當日經過的時間表示
+const (
+	sessionOpen           = 9 * time.Hour
+	sessionClosingAuction = 13*time.Hour + 25*time.Minute
+	sessionClose          = 13*time.Hour + 30*time.Minute
+)
+
+// sessionOf returns the trading session at t, trial reports whether the
+// price comes from a simulated match which only matters after the close.
+func sessionOf(t time.Time, trial bool) Session {
+	if t.IsZero() {
+		return SessionUnknown
+	}
+	t = t.In(taipei)
+	elapsed := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, taipei))
+	switch {
+	case elapsed < sessionOpen:
+		return SessionPreOpen
+	case elapsed < sessionClosingAuction:
+		// 盤中的試撮例如瞬間價格穩定措施的集合競價仍屬於盤中，由 Trial 區分
+		return SessionContinuous
+	case elapsed < sessionClose:
+		return SessionClosingAuction
+	case trial:
+		// 收盤時間過後仍在試撮代表暫緩收盤
+		return SessionClosingAuction
+	default:
+		return SessionClosed
+	}
 }
 
 type realtimeOptions struct {
 	Codes string `url:"ex_ch"`
 }
 
+// 臺灣不實施夏令時間，系統缺少時區資料時以固定時差代替
+var taipei = loadTaipei()
+
+// today returns the current date in Taipei, the exchanges publish nothing
+// after it.
+func today() civil.Date {
+	return civil.DateOf(time.Now().In(taipei))
+}
+
+func loadTaipei() *time.Location {
+	loc, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		return time.FixedZone("Asia/Taipei", 8*60*60)
+	}
+	return loc
+}
+
 type timestamp struct {
 	time.Time
 }
@@ -391,7 +649,7 @@ func (p *timestamp) UnmarshalJSON(bytes []byte) error {
 		return err
 	}
 	// realtime returns Unix timestamp in milliseconds
-	p.Time = time.Unix(i/1000, (i % 1000 * 1000000)).In(time.UTC)
+	p.Time = time.Unix(i/1000, (i % 1000 * 1000000)).In(taipei)
 	return nil
 }
 
@@ -409,6 +667,28 @@ type realtimeData struct {
 	Volume     string    `json:"v"`
 	Name       string    `json:"n"`
 	FullName   string    `json:"nf"`
+
+	PreviousClose   string `json:"y"`
+	LimitUp         string `json:"u"`
+	LimitDown       string `json:"w"`
+	LastTradeVolume string `json:"tv"`
+	Exchange        string `json:"ex"`
+	Date            string `json:"d"`
+	Time            string `json:"t"`
+	Trial           string `json:"ts"`
+}
+
+// at returns the quote time, falling back to the date and time fields when
+// tlong is missing.
+func (data realtimeData) at() time.Time {
+	if !data.Timestamp.IsZero() || data.Date == "" || data.Time == "" {
+		return data.Timestamp.Time
+	}
+	t, err := time.ParseInLocation("20060102 15:04:05", data.Date+" "+data.Time, taipei)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
 
 type realtimeResponse struct {
@@ -416,7 +696,30 @@ type realtimeResponse struct {
 	Data []realtimeData `json:"msgArray"`
 }
 
+// 尚未成交或沒有委託時基本市況報導網站會回傳 "-"
+const realtimeNoValue = "-"
+
+// parseRealtimePrice returns false without error when the price is missing.
+func parseRealtimePrice(s string) (decimal.Decimal, bool, error) {
+	if s == realtimeNoValue || s == "" {
+		return decimal.Zero, false, nil
+	}
+	v, err := parsePrice(s)
+	return v, err == nil, err
+}
+
+// parseRealtimeVolume returns 0 without error when the volume is missing.
+func parseRealtimeVolume(s string) (int, error) {
+	if s == realtimeNoValue || s == "" {
+		return 0, nil
+	}
+	return parseVolume(s)
+}
+
 func parseBidAsk(pricesStr string, volumesStr string) ([]BidAsk, error) {
+	if pricesStr == realtimeNoValue && volumesStr == realtimeNoValue {
+		return []BidAsk{}, nil
+	}
 	split := func(v string) []string { return strings.Split(strings.Trim(v, "_"), "_") }
 
 	prices := split(pricesStr)
@@ -442,23 +745,23 @@ func parseBidAsk(pricesStr string, volumesStr string) ([]BidAsk, error) {
 
 func parseRealtimeData(data realtimeData) (RealtimeQuote, error) {
 	var quote RealtimeQuote
-	price, err := parsePrice(data.Price)
+	price, hasTrade, err := parseRealtimePrice(data.Price)
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote price: %w", err)
 	}
-	open, err := parsePrice(data.Open)
+	open, _, err := parseRealtimePrice(data.Open)
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote open: %w", err)
 	}
-	high, err := parsePrice(data.High)
+	high, _, err := parseRealtimePrice(data.High)
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote high: %w", err)
 	}
-	low, err := parsePrice(data.Low)
+	low, _, err := parseRealtimePrice(data.Low)
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote low: %w", err)
 	}
-	volume, err := parseVolume(data.Volume)
+	volume, err := parseRealtimeVolume(data.Volume)
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote volume: %w", err)
 	}
@@ -470,8 +773,36 @@ func parseRealtimeData(data realtimeData) (RealtimeQuote, error) {
 	if err != nil {
 		return quote, fmt.Errorf("failed parsing quote asks: %w", err)
 	}
+	previousClose, _, err := parseRealtimePrice(data.PreviousClose)
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote previous close: %w", err)
+	}
+	limitUp, _, err := parseRealtimePrice(data.LimitUp)
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote limit up: %w", err)
+	}
+	limitDown, _, err := parseRealtimePrice(data.LimitDown)
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote limit down: %w", err)
+	}
+	lastTradeVolume, err := parseRealtimeVolume(data.LastTradeVolume)
+	if err != nil {
+		return quote, fmt.Errorf("failed parsing quote last trade volume: %w", err)
+	}
+	if hasTrade && !previousClose.IsZero() {
+		quote.Change = price.Sub(previousClose)
+		quote.ChangePercent = quote.Change.Div(previousClose).Mul(decimal.NewFromInt(100)).Round(2)
+	}
+	// 尚未成交時以最佳委買價或最佳委賣價代替
+	if !hasTrade {
+		if len(bids) > 0 {
+			price = bids[0].Price
+		} else if len(asks) > 0 {
+			price = asks[0].Price
+		}
+	}
 
-	quote.At = data.Timestamp.Time
+	quote.At = data.at()
 	quote.Code = data.Code
 	quote.Name = data.Name
 	quote.FullName = data.FullName
@@ -482,50 +813,196 @@ func parseRealtimeData(data realtimeData) (RealtimeQuote, error) {
 	quote.Volume = volume
 	quote.Bids = bids
 	quote.Asks = asks
+	quote.HasTrade = hasTrade
+	quote.PreviousClose = previousClose
+	quote.LimitUp = limitUp
+	quote.LimitDown = limitDown
+	quote.LastTradeVolume = lastTradeVolume
+	quote.Exchange = Market(data.Exchange)
+	quote.Trial = data.Trial == "1"
+	quote.Session = sessionOf(quote.At, quote.Trial)
 
 	return quote, nil
 }
 
-// 從台灣證卷交易所下載即時個股成交資訊
-func (s *QuoteService) Realtime(codes ...string) (map[string]RealtimeQuote, error) {
-	for i, v := range codes {
-		//nolint:typecheck
-		if security, ok := Securities[v]; ok {
-			if security.Market == TWSE {
-				codes[i] = fmt.Sprintf("%s_%s.tw", TWSE, v)
-				continue
-			} else if security.Market == TPEx {
-				codes[i] = fmt.Sprintf("%s_%s.tw", TPEx, v)
-				continue
+// realtime queries the given channels such as tse_2330.tw from the market
+// information system, splitting them into batches the API accepts. codes are
+// the codes of the channels, the error of a failed batch is recorded under
+// each of its codes while the other batches are still returned. A plain error
+// is returned only when every batch fails or ctx is done.
+func (s *QuoteService) realtime(ctx context.Context, path string, channels, codes []string) ([]realtimeData, CodeErrors, error) {
+	batchSize := s.client.realtimeBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRealtimeBatchSize
+	}
+	result := []realtimeData{}
+	errs := CodeErrors{}
+	var lastErr error
+	succeeded := false
+	for start := 0; start < len(channels); start += batchSize {
+		end := min(start+batchSize, len(channels))
+		err := s.realtimeBatch(ctx, path, channels[start:end], &result)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		if err != nil {
+			for _, code := range codes[start:end] {
+				errs[code] = err
 			}
+			lastErr = err
+			continue
 		}
-		return nil, fmt.Errorf("invalid code: %s", v)
+		succeeded = true
+	}
+	if !succeeded && lastErr != nil {
+		return nil, nil, lastErr
 	}
+	return result, errs, nil
+}
 
-	url, _ := s.client.misTwseBaseURL.Parse(realtimeQuotesPath)
+// realtimeBatch queries a single batch of channels and appends the data to
+// result.
+func (s *QuoteService) realtimeBatch(ctx context.Context, path string, channels []string, result *[]realtimeData) error {
+	url, _ := s.client.misTwseBaseURL.Parse(path)
 	opts := realtimeOptions{
-		Codes: strings.Join(codes, "|"),
+		Codes: strings.Join(channels, "|"),
 	}
 	url, _ = addOptions(url, opts)
-	req, _ := s.client.NewRequest("GET", url.String(), nil)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	resp := &realtimeResponse{}
 	_, err := s.client.Do(req, &resp)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.Stat != "OK" {
-		return nil, fmt.Errorf("invalid state: %s", resp.Stat)
+		return newStatError(url, resp.Stat)
+	}
+	*result = append(*result, resp.Data...)
+	return nil
+}
+
+// CodeErrors 記錄部分代號查詢失敗的原因，其餘代號的結果仍會正常回傳
+type CodeErrors map[string]error
+
+func (e CodeErrors) Error() string {
+	codes := e.codes()
+	messages := make([]string, 0, len(codes))
+	for _, code := range codes {
+		messages = append(messages, fmt.Sprintf("%s: %v", code, e[code]))
+	}
+	return "failed codes: " + strings.Join(messages, "; ")
+}
+
+// Unwrap allows errors.Is and errors.As to match the error of any code.
+func (e CodeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, code := range e.codes() {
+		errs = append(errs, e[code])
+	}
+	return errs
+}
+
+func (e CodeErrors) codes() []string {
+	codes := make([]string, 0, len(e))
+	for code := range e {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// errOrNil returns nil instead of an empty CodeErrors.
+func (e CodeErrors) errOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// 即時行情查詢的標的
+type Instrument struct {
+	Code   string // 有價證券代號
+	Market Market // 市場別，為空時依 Securities 判斷，新上市櫃或權證等不在 Securities 的代號需指定
+}
+
+// 將有價證券代號轉換為 Instrument，市場別在查詢時依 Securities 判斷
+func Instruments(codes ...string) []Instrument {
+	instruments := make([]Instrument, 0, len(codes))
+	for _, code := range codes {
+		instruments = append(instruments, Instrument{Code: code})
+	}
+	return instruments
+}
+
+// channel returns the market information system channel such as tse_2330.tw.
+func (i Instrument) channel() (string, error) {
+	market := i.Market
+	if market == "" {
+		//nolint:typecheck
+		security, ok := Securities[i.Code]
+		if !ok {
+			return "", fmt.Errorf("invalid code: %s", i.Code)
+		}
+		market = security.Market
+	}
+	if i.Code == "" || (market != TWSE && market != TPEx) {
+		return "", fmt.Errorf("invalid code: %s", i.Code)
+	}
+	return fmt.Sprintf("%s_%s.tw", market, i.Code), nil
+}
+
+// 從台灣證卷交易所下載即時個股成交資訊，代號過多時會自動分批查詢，
+// 部分代號查詢失敗時仍會回傳其餘代號的結果以及 CodeErrors
+func (s *QuoteService) Realtime(instruments ...Instrument) (map[string]RealtimeQuote, error) {
+	return s.RealtimeWithContext(context.Background(), instruments...)
+}
+
+// 從台灣證卷交易所下載即時個股成交資訊，可透過 ctx 取消請求，
+// 部分代號查詢失敗時仍會回傳其餘代號的結果以及 CodeErrors
+func (s *QuoteService) RealtimeWithContext(ctx context.Context, instruments ...Instrument) (map[string]RealtimeQuote, error) {
+	return s.realtimeQuotes(ctx, realtimeQuotesPath, instruments)
+}
+
+func (s *QuoteService) realtimeQuotes(ctx context.Context, path string, instruments []Instrument) (map[string]RealtimeQuote, error) {
+	channels := make([]string, 0, len(instruments))
+	codes := make([]string, 0, len(instruments))
+	for _, instrument := range instruments {
+		channel, err := instrument.channel()
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+		codes = append(codes, instrument.Code)
+	}
+
+	result, errs, err := s.realtime(ctx, path, channels, codes)
+	if err != nil {
+		return nil, err
 	}
 
 	quotes := map[string]RealtimeQuote{}
-	for _, data := range resp.Data {
+	for _, data := range result {
 		quote, err := parseRealtimeData(data)
 		if err != nil {
-			return nil, err
+			errs[data.Code] = err
+			continue
 		}
+		quote.OddLot = path == realtimeOddLotQuotesPath
 		quotes[data.Code] = quote
 	}
+	for _, instrument := range instruments {
+		code := instrument.Code
+		if _, ok := quotes[code]; !ok && errs[code] == nil {
+			errs[code] = fmt.Errorf("%w: %s", ErrNoData, code)
+		}
+	}
 
-	return quotes, nil
+	return quotes, errs.errOrNil()
+}
+
+// 從台灣證卷交易所下載盤中零股即時成交資訊，成交量及委買委賣量的單位為股，
+// 部分代號查詢失敗時仍會回傳其餘代號的結果以及 CodeErrors
+func (s *QuoteService) RealtimeOddLot(ctx context.Context, instruments ...Instrument) (map[string]RealtimeQuote, error) {
+	return s.realtimeQuotes(ctx, realtimeOddLotQuotesPath, instruments)
 }
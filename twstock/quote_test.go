@@ -1,6 +1,7 @@
 package twstock
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -111,36 +112,48 @@ func TestQuoteService_DownloadTwse(t *testing.T) {
 	}
 	want := []Quote{
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 1},
-			Open:   decimal.NewFromFloat(506),
-			High:   decimal.NewFromFloat(508),
-			Low:    decimal.NewFromFloat(500),
-			Close:  decimal.NewFromFloat(504),
-			Volume: 24991291,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 1},
+			Open:         decimal.NewFromFloat(506),
+			High:         decimal.NewFromFloat(508),
+			Low:          decimal.NewFromFloat(500),
+			Close:        decimal.NewFromFloat(504),
+			Volume:       24991291,
+			TradeValue:   decimal.NewFromInt(12569771761),
+			Change:       decimal.NewFromFloat(-5),
+			Transactions: 26792,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 2},
-			Open:   decimal.NewFromFloat(494),
-			High:   decimal.NewFromFloat(496),
-			Low:    decimal.NewFromFloat(488.50),
-			Close:  decimal.NewFromFloat(492),
-			Volume: 42669591,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 2},
+			Open:         decimal.NewFromFloat(494),
+			High:         decimal.NewFromFloat(496),
+			Low:          decimal.NewFromFloat(488.50),
+			Close:        decimal.NewFromFloat(492),
+			Volume:       42669591,
+			TradeValue:   decimal.NewFromInt(20973293337),
+			Change:       decimal.NewFromFloat(-12),
+			Transactions: 63879,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 4},
-			Open:   decimal.NewFromFloat(499),
-			High:   decimal.NewFromFloat(503),
-			Low:    decimal.NewFromFloat(495),
-			Close:  decimal.NewFromFloat(500),
-			Volume: 26589086,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 4},
+			Open:         decimal.NewFromFloat(499),
+			High:         decimal.NewFromFloat(503),
+			Low:          decimal.NewFromFloat(495),
+			Close:        decimal.NewFromFloat(500),
+			Volume:       26589086,
+			TradeValue:   decimal.NewFromInt(13279624282),
+			Change:       decimal.NewFromFloat(-1),
+			Transactions: 27173,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 5},
-			Open:   decimal.NewFromFloat(509),
-			High:   decimal.NewFromFloat(516),
-			Low:    decimal.NewFromFloat(507),
-			Close:  decimal.NewFromFloat(516),
-			Volume: 35052642,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 5},
+			Open:         decimal.NewFromFloat(509),
+			High:         decimal.NewFromFloat(516),
+			Low:          decimal.NewFromFloat(507),
+			Close:        decimal.NewFromFloat(516),
+			Volume:       35052642,
+			TradeValue:   decimal.NewFromInt(17966410242),
+			Change:       decimal.NewFromFloat(16),
+			Transactions: 49928,
 		},
 	}
 	if !cmp.Equal(quotes, want) {
@@ -179,6 +192,27 @@ func TestQuoteService_DownloadTwseError(t *testing.T) {
 	}
 }
 
+func TestQuoteService_DownloadWithContextCanceled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Quote.DownloadWithContext sent a request with a canceled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Quote.DownloadWithContext(ctx, "2330", 2022, 8)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Quote.DownloadWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = client.Quote.RealtimeWithContext(ctx, Instruments("2330")...)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Quote.RealtimeWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestQuoteService_DownloadTwseErrNoData(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -206,6 +240,13 @@ func TestQuoteService_DownloadTwseErrNoData(t *testing.T) {
 	if !errors.Is(err, ErrNoData) {
 		t.Errorf("Quote.DownloadTwse returned %v, want %v", err, ErrNoData)
 	}
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Fatalf("Quote.DownloadTwse returned %T, want *StatError", err)
+	}
+	if statErr.Endpoint != twseQuotesPath || statErr.Params.Get("stockNo") != "2330" || !statErr.IsUnknownCode() {
+		t.Errorf("Quote.DownloadTwse returned %+v, want unknown code error for 2330", statErr)
+	}
 }
 
 func TestQuoteService_DownloadTwseErrDateOutOffRange(t *testing.T) {
@@ -488,44 +529,59 @@ func TestQuoteService_DownloadTpex(t *testing.T) {
 	}
 	want := []Quote{
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 1},
-			Open:   decimal.NewFromFloat(127.5),
-			High:   decimal.NewFromFloat(128),
-			Low:    decimal.NewFromFloat(125.5),
-			Close:  decimal.NewFromFloat(127),
-			Volume: 1328000,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 1},
+			Open:         decimal.NewFromFloat(127.5),
+			High:         decimal.NewFromFloat(128),
+			Low:          decimal.NewFromFloat(125.5),
+			Close:        decimal.NewFromFloat(127),
+			Volume:       1328000,
+			TradeValue:   decimal.NewFromInt(168265000),
+			Change:       decimal.NewFromFloat(-2),
+			Transactions: 1272,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 2},
-			Open:   decimal.NewFromFloat(125),
-			High:   decimal.NewFromFloat(127),
-			Low:    decimal.NewFromFloat(123),
-			Close:  decimal.NewFromFloat(127),
-			Volume: 1593000,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 2},
+			Open:         decimal.NewFromFloat(125),
+			High:         decimal.NewFromFloat(127),
+			Low:          decimal.NewFromFloat(123),
+			Close:        decimal.NewFromFloat(127),
+			Volume:       1593000,
+			TradeValue:   decimal.NewFromInt(199305000),
+			Change:       decimal.NewFromFloat(0),
+			Transactions: 1078,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 3},
-			Open:   decimal.NewFromFloat(124.5),
-			High:   decimal.NewFromFloat(127),
-			Low:    decimal.NewFromFloat(124),
-			Close:  decimal.NewFromFloat(126),
-			Volume: 1603000,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 3},
+			Open:         decimal.NewFromFloat(124.5),
+			High:         decimal.NewFromFloat(127),
+			Low:          decimal.NewFromFloat(124),
+			Close:        decimal.NewFromFloat(126),
+			Volume:       1603000,
+			TradeValue:   decimal.NewFromInt(201304000),
+			Change:       decimal.NewFromFloat(-1),
+			Transactions: 1124,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 4},
-			Open:   decimal.NewFromFloat(126.5),
-			High:   decimal.NewFromFloat(130),
-			Low:    decimal.NewFromFloat(124.5),
-			Close:  decimal.NewFromFloat(129.5),
-			Volume: 3920000,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 4},
+			Open:         decimal.NewFromFloat(126.5),
+			High:         decimal.NewFromFloat(130),
+			Low:          decimal.NewFromFloat(124.5),
+			Close:        decimal.NewFromFloat(129.5),
+			Volume:       3920000,
+			TradeValue:   decimal.NewFromInt(500389000),
+			Change:       decimal.NewFromFloat(3.5),
+			Transactions: 2474,
 		},
 		{
-			Date:   civil.Date{Year: 2022, Month: time.August, Day: 5},
-			Open:   decimal.NewFromFloat(129.5),
-			High:   decimal.NewFromFloat(132),
-			Low:    decimal.NewFromFloat(126.5),
-			Close:  decimal.NewFromFloat(129.5),
-			Volume: 7244000,
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 5},
+			Open:         decimal.NewFromFloat(129.5),
+			High:         decimal.NewFromFloat(132),
+			Low:          decimal.NewFromFloat(126.5),
+			Close:        decimal.NewFromFloat(129.5),
+			Volume:       7244000,
+			TradeValue:   decimal.NewFromInt(940126000),
+			Change:       decimal.NewFromFloat(0),
+			Transactions: 5073,
 		},
 	}
 	if !cmp.Equal(quotes, want) {
@@ -696,6 +752,52 @@ func TestQuoteService_DownloadTpexErrSuspendedTrading(t *testing.T) {
 	if len(quotes) != 0 {
 		t.Errorf("Quote.Download returned %d quotes, want 0", len(quotes))
 	}
+	quotes, err = client.Quote.DownloadWithSuspended(context.Background(), "3374", 2022, 8)
+	if err != nil {
+		t.Errorf("Quote.DownloadWithSuspended returned error: %v", err)
+	}
+	want := []Quote{
+		{
+			Date:         civil.Date{Year: 2022, Month: time.August, Day: 1},
+			Volume:       1328000,
+			TradeValue:   decimal.NewFromInt(168265000),
+			Transactions: 1272,
+			Suspended:    true,
+		},
+	}
+	if !cmp.Equal(quotes, want) {
+		t.Errorf("Quote.DownloadWithSuspended returned %v, want %v", quotes, want)
+	}
+}
+
+func TestQuoteService_DownloadWithSuspendedTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"stat": "OK",
+			"fields": ["日期","成交股數","成交金額","開盤價","最高價","最低價","收盤價","漲跌價差","成交筆數","註記"],
+			"data": [
+				["111/08/01","1,000","10,000","10.00","10.00","10.00","10.00","0.00","5",""],
+				["111/08/02","0","0","--","--","--","--"," 0.00","0",""]
+			]
+		}`)
+	})
+
+	quotes, err := client.Quote.DownloadWithSuspended(context.Background(), "2330", 2022, 8)
+	if err != nil {
+		t.Fatalf("Quote.DownloadWithSuspended returned error: %v", err)
+	}
+	if len(quotes) != 2 || quotes[0].Suspended || !quotes[1].Suspended {
+		t.Errorf("Quote.DownloadWithSuspended returned %v, want second day suspended", quotes)
+	}
+
+	_, err = client.Quote.DownloadWithSuspended(context.Background(), "BAD", 2022, 8)
+	if err == nil {
+		t.Error("Quote.DownloadWithSuspended returned nil; expected error")
+	}
 }
 
 func TestQuoteService_DownloadBadCode(t *testing.T) {
@@ -888,6 +990,123 @@ func TestQuoteService_DownloadBadData(t *testing.T) {
 	}
 }
 
+func TestQuoteService_DownloadRange(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requested := []string{}
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		date := r.URL.Query().Get("date")
+		requested = append(requested, date)
+		rows := map[string]string{
+			"20100101": `["99/01/04","1","1","1.00","1.00","1.00","1.00","0.00","1",""]`,
+			"20220701": `["111/07/28","1","1","1.00","1.00","1.00","1.00","0.00","1",""],
+				["111/07/29","2","2","2.00","2.00","2.00","2.00","0.00","2",""]`,
+			"20220801": `["111/08/02","4","4","4.00","4.00","4.00","4.00","0.00","4",""],
+				["111/08/01","3","3","3.00","3.00","3.00","3.00","0.00","3",""],
+				["111/08/02","4","4","4.00","4.00","4.00","4.00","0.00","4",""]`,
+		}[date]
+		if rows == "" {
+			fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"stat": "OK",
+			"fields": ["日期","成交股數","成交金額","開盤價","最高價","最低價","收盤價","漲跌價差","成交筆數","註記"],
+			"data": [%s]
+		}`, rows)
+	})
+
+	from := civil.Date{Year: 2022, Month: time.July, Day: 29}
+	to := civil.Date{Year: 2022, Month: time.September, Day: 15}
+	quotes, err := client.Quote.DownloadRange(context.Background(), "2330", from, to)
+	if err != nil {
+		t.Fatalf("Quote.DownloadRange returned error: %v", err)
+	}
+	got := []civil.Date{}
+	for _, quote := range quotes {
+		got = append(got, quote.Date)
+	}
+	want := []civil.Date{
+		{Year: 2022, Month: time.July, Day: 29},
+		{Year: 2022, Month: time.August, Day: 1},
+		{Year: 2022, Month: time.August, Day: 2},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Quote.DownloadRange returned %v, want %v", got, want)
+	}
+	if !cmp.Equal(requested, []string{"20220701", "20220801", "20220901"}) {
+		t.Errorf("Quote.DownloadRange requested %v", requested)
+	}
+
+	// 早於最小查詢日期的月份不會送出請求
+	requested = []string{}
+	from = civil.Date{Year: 2009, Month: time.November, Day: 1}
+	to = civil.Date{Year: 2010, Month: time.January, Day: 31}
+	quotes, err = client.Quote.DownloadRange(context.Background(), "2330", from, to)
+	if err != nil {
+		t.Fatalf("Quote.DownloadRange returned error: %v", err)
+	}
+	if len(quotes) != 1 || !cmp.Equal(requested, []string{"20100101"}) {
+		t.Errorf("Quote.DownloadRange returned %v after requesting %v", quotes, requested)
+	}
+}
+
+func TestQuoteService_DownloadRangeFuture(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requested := []string{}
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("date"))
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	now := today()
+	_, err := client.Quote.DownloadRange(context.Background(), "2330", now, civil.Date{Year: 2100, Month: time.January, Day: 1})
+	if err != nil {
+		t.Fatalf("Quote.DownloadRange returned error: %v", err)
+	}
+	if want := []string{fmt.Sprintf("%04d%02d01", now.Year, now.Month)}; !cmp.Equal(requested, want) {
+		t.Errorf("Quote.DownloadRange requested %v, want %v", requested, want)
+	}
+}
+
+func TestQuoteService_DownloadRangeError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	from := civil.Date{Year: 2022, Month: time.August, Day: 1}
+	to := civil.Date{Year: 2022, Month: time.August, Day: 31}
+	_, err := client.Quote.DownloadRange(context.Background(), "2330", from, to)
+	if err == nil {
+		t.Error("Quote.DownloadRange returned nil; expected error")
+	}
+	testErrorContains(t, err, ": 400")
+
+	_, err = client.Quote.DownloadRange(context.Background(), "2330", to, from)
+	if err == nil {
+		t.Error("Quote.DownloadRange returned nil; expected error")
+	}
+
+	_, err = client.Quote.DownloadRange(context.Background(), "BAD", from, to)
+	if err == nil {
+		t.Error("Quote.DownloadRange returned nil; expected error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Quote.DownloadRange(ctx, "2330", from, to)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Quote.DownloadRange returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestQuoteService_parse(t *testing.T) {
 	client, _, teardown := setup()
 	defer teardown()
@@ -993,6 +1212,40 @@ func TestQuoteService_parse(t *testing.T) {
 			"-2.00",
 			"1,272",
 		},
+
+		[]string{
+			"111/08/01",
+			"1,328",
+			"a",
+			"127.50",
+			"128.00",
+			"125.50",
+			"127.00",
+			"-2.00",
+			"1,272",
+		},
+		[]string{
+			"111/08/01",
+			"1,328",
+			"168,265",
+			"127.50",
+			"128.00",
+			"125.50",
+			"127.00",
+			"a",
+			"1,272",
+		},
+		[]string{
+			"111/08/01",
+			"1,328",
+			"168,265",
+			"127.50",
+			"128.00",
+			"125.50",
+			"127.00",
+			"-2.00",
+			"a",
+		},
 	}
 
 	for _, test := range testCases {
@@ -1005,6 +1258,134 @@ func TestQuoteService_parse(t *testing.T) {
 	}
 }
 
+func TestQuoteService_parseNote(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	quote, err := client.Quote.parse([]string{
+		"111/06/16",
+		"28,350,012",
+		"14,961,113,004",
+		"530.00",
+		"531.00",
+		"525.00",
+		"526.00",
+		"X0.00",
+		"45,117",
+		"除息",
+	})
+	if err != nil {
+		t.Fatalf("client.Quote.parse returned error: %v", err)
+	}
+	want := Quote{
+		Date:         civil.Date{Year: 2022, Month: time.June, Day: 16},
+		Open:         decimal.NewFromInt(530),
+		High:         decimal.NewFromInt(531),
+		Low:          decimal.NewFromInt(525),
+		Close:        decimal.NewFromInt(526),
+		Volume:       28350012,
+		TradeValue:   decimal.NewFromInt(14961113004),
+		Change:       decimal.Zero,
+		Transactions: 45117,
+		Note:         "除息",
+	}
+	if !cmp.Equal(quote, want) {
+		t.Errorf("client.Quote.parse returned %v, want %v", quote, want)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	var testCases = map[string]struct {
+		value     string
+		want      decimal.Decimal
+		wantError bool
+	}{
+		"integer":   {"506", decimal.NewFromInt(506), false},
+		"comma":     {"12,569,771,761", decimal.NewFromInt(12569771761), false},
+		"plus":      {"+9.00", decimal.NewFromInt(9), false},
+		"minus":     {"-12.50", decimal.RequireFromString("-12.5"), false},
+		"space":     {" 0.00", decimal.Zero, false},
+		"exact":     {"9,007,199,254,740,993.01", decimal.RequireFromString("9007199254740993.01"), false},
+		"suspended": {"--", decimal.Zero, true},
+		"empty":     {"", decimal.Zero, true},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parsePrice(test.value)
+			if err != nil && !test.wantError {
+				t.Errorf("parsePrice(%q) returned error: %v", test.value, err)
+			}
+			if err == nil && test.wantError {
+				t.Errorf("parsePrice(%q) returned nil; expected error", test.value)
+			}
+			if !test.wantError && !got.Equal(test.want) {
+				t.Errorf("parsePrice(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTpexTableDate(t *testing.T) {
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	var testCases = map[string]struct {
+		value     string
+		want      civil.Date
+		wantError bool
+	}{
+		"empty":     {"", date, false},
+		"roc":       {"111/08/19", civil.Date{Year: 2022, Month: time.August, Day: 19}, false},
+		"gregorian": {"20220819", civil.Date{Year: 2022, Month: time.August, Day: 19}, false},
+		"invalid":   {"20221341", date, true},
+		"bad":       {"BAD", date, true},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tpexTableDate(date, test.value)
+			if err != nil && !test.wantError {
+				t.Errorf("tpexTableDate(%q) returned error: %v", test.value, err)
+			}
+			if err == nil && test.wantError {
+				t.Errorf("tpexTableDate(%q) returned nil; expected error", test.value)
+			}
+			if !test.wantError && got != test.want {
+				t.Errorf("tpexTableDate(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseChange(t *testing.T) {
+	var testCases = map[string]struct {
+		value     string
+		want      decimal.Decimal
+		wantError bool
+	}{
+		"plus":       {"+16.00", decimal.NewFromInt(16), false},
+		"minus":      {"-5.00", decimal.NewFromInt(-5), false},
+		"x prefix":   {"X0.00", decimal.Zero, false},
+		"x negative": {"X-1.50", decimal.RequireFromString("-1.5"), false},
+		"marker":     {"除權息", decimal.Zero, false},
+		"marker+":    {"除息 +0.50", decimal.RequireFromString("0.5"), false},
+		"html":       {"<p style= color:green>-</p>2.50", decimal.RequireFromString("-2.5"), false},
+		"empty":      {"", decimal.Zero, false},
+		"invalid":    {"a", decimal.Zero, true},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseChange(test.value)
+			if err != nil && !test.wantError {
+				t.Errorf("parseChange(%q) returned error: %v", test.value, err)
+			}
+			if err == nil && test.wantError {
+				t.Errorf("parseChange(%q) returned nil; expected error", test.value)
+			}
+			if !test.wantError && !got.Equal(test.want) {
+				t.Errorf("parseChange(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
 func TestParseBidAsk(t *testing.T) {
 	var testCases = []struct {
 		pricesStr  string
@@ -1033,7 +1414,7 @@ func TestTimestamp_UnmarshalJSON(t *testing.T) {
 	}{
 		"valid": {
 			data:      []byte(`"1640567145000"`),
-			want:      timestamp{Time: time.Date(2021, 12, 27, 1, 5, 45, 0, time.UTC)},
+			want:      timestamp{Time: time.Date(2021, 12, 27, 9, 5, 45, 0, taipei)},
 			wantError: false,
 		},
 		"not string": {
@@ -1057,7 +1438,7 @@ func TestTimestamp_UnmarshalJSON(t *testing.T) {
 			if err == nil && test.wantError {
 				t.Errorf("Timestamp.UnmarshalJSON returned no error when we expected one")
 			}
-			if !cmp.Equal(test.want, date) {
+			if !cmp.Equal(test.want, date) || date.Location() != test.want.Location() {
 				t.Errorf("Timestamp.UnmarshalJSON expected date %v, got %v", test.want, date)
 			}
 		})
@@ -1101,6 +1482,134 @@ func TestParseRealtimeData(t *testing.T) {
 	}
 }
 
+func TestParseRealtimeDataNoTrade(t *testing.T) {
+	var testCases = map[string]struct {
+		data  realtimeData
+		price decimal.Decimal
+	}{
+		"bid": {
+			data:  realtimeData{Price: "-", Open: "-", High: "-", Low: "-", Volume: "0", BidPrices: "99.5000_99.0000_", BidVolumes: "3_5_", AskPrices: "-", AskVolumes: "-"},
+			price: decimal.RequireFromString("99.5"),
+		},
+		"ask": {
+			data:  realtimeData{Price: "-", Open: "-", High: "-", Low: "-", Volume: "0", BidPrices: "-", BidVolumes: "-", AskPrices: "101.0000_", AskVolumes: "2_"},
+			price: decimal.NewFromInt(101),
+		},
+		"none": {
+			data:  realtimeData{Price: "-", Open: "-", High: "-", Low: "-", Volume: "0", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-"},
+			price: decimal.Zero,
+		},
+		"no volume": {
+			data:  realtimeData{Price: "-", Open: "-", High: "-", Low: "-", Volume: "-", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-"},
+			price: decimal.Zero,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			quote, err := parseRealtimeData(test.data)
+			if err != nil {
+				t.Fatalf("parseRealtimeData returned error: %v", err)
+			}
+			if quote.HasTrade {
+				t.Error("parseRealtimeData returned HasTrade = true, want false")
+			}
+			if !quote.Price.Equal(test.price) {
+				t.Errorf("parseRealtimeData returned price %v, want %v", quote.Price, test.price)
+			}
+			if !quote.Open.IsZero() || !quote.High.IsZero() || !quote.Low.IsZero() || quote.Volume != 0 {
+				t.Errorf("parseRealtimeData returned %v, want zero open, high, low and volume", quote)
+			}
+		})
+	}
+}
+
+func TestParseRealtimeDataReference(t *testing.T) {
+	data := realtimeData{
+		Price: "10.5000", Open: "10.0000", High: "10.5000", Low: "10.0000", Volume: "12",
+		BidPrices: "10.4500_", BidVolumes: "1_", AskPrices: "10.5000_", AskVolumes: "2_",
+		PreviousClose: "10.0000", LimitUp: "11.0000", LimitDown: "9.0000", LastTradeVolume: "3", Exchange: "otc",
+	}
+	quote, err := parseRealtimeData(data)
+	if err != nil {
+		t.Fatalf("parseRealtimeData returned error: %v", err)
+	}
+	if !quote.Change.Equal(decimal.NewFromFloat(0.5)) || !quote.ChangePercent.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("parseRealtimeData returned change %v (%v%%), want 0.5 (5%%)", quote.Change, quote.ChangePercent)
+	}
+	if quote.LastTradeVolume != 3 || quote.Exchange != TPEx {
+		t.Errorf("parseRealtimeData returned %+v, want last trade volume 3 on %s", quote, TPEx)
+	}
+
+	for _, bad := range []realtimeData{
+		{Price: "1", Open: "1", High: "1", Low: "1", Volume: "1", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-", PreviousClose: "BAD"},
+		{Price: "1", Open: "1", High: "1", Low: "1", Volume: "1", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-", LimitUp: "BAD"},
+		{Price: "1", Open: "1", High: "1", Low: "1", Volume: "1", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-", LimitDown: "BAD"},
+		{Price: "1", Open: "1", High: "1", Low: "1", Volume: "1", BidPrices: "-", BidVolumes: "-", AskPrices: "-", AskVolumes: "-", LastTradeVolume: "BAD"},
+	} {
+		if _, err := parseRealtimeData(bad); err == nil {
+			t.Errorf("parseRealtimeData(%+v) returned nil; expected error", bad)
+		}
+	}
+}
+
+func TestParseRealtimeDataSession(t *testing.T) {
+	var testCases = map[string]struct {
+		data    realtimeData
+		trial   bool
+		session Session
+	}{
+		"pre-open": {
+			data:    realtimeData{Date: "20220822", Time: "08:59:55", Trial: "1"},
+			trial:   true,
+			session: SessionPreOpen,
+		},
+		"continuous": {
+			data:    realtimeData{Date: "20220822", Time: "10:15:00", Trial: "0"},
+			session: SessionContinuous,
+		},
+		"mid-session trial": {
+			data:    realtimeData{Date: "20220822", Time: "10:15:00", Trial: "1"},
+			trial:   true,
+			session: SessionContinuous,
+		},
+		"closing auction": {
+			data:    realtimeData{Date: "20220822", Time: "13:26:30", Trial: "1"},
+			trial:   true,
+			session: SessionClosingAuction,
+		},
+		"delayed close": {
+			data:    realtimeData{Date: "20220822", Time: "13:31:00", Trial: "1"},
+			trial:   true,
+			session: SessionClosingAuction,
+		},
+		"closed": {
+			data:    realtimeData{Date: "20220822", Time: "13:30:00", Trial: "0"},
+			session: SessionClosed,
+		},
+		"unknown": {
+			data:    realtimeData{},
+			session: SessionUnknown,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := test.data
+			data.Price, data.Open, data.High, data.Low, data.Volume = "-", "-", "-", "-", "0"
+			data.BidPrices, data.BidVolumes, data.AskPrices, data.AskVolumes = "-", "-", "-", "-"
+			quote, err := parseRealtimeData(data)
+			if err != nil {
+				t.Fatalf("parseRealtimeData returned error: %v", err)
+			}
+			if quote.Trial != test.trial || quote.Session != test.session {
+				t.Errorf("parseRealtimeData returned trial %v session %q, want %v %q", quote.Trial, quote.Session, test.trial, test.session)
+			}
+			if !quote.At.IsZero() && quote.At.Location() != taipei {
+				t.Errorf("parseRealtimeData returned location %v, want %v", quote.At.Location(), taipei)
+			}
+		})
+	}
+}
+
 func TestQuoteService_Realtime(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -1206,13 +1715,13 @@ func TestQuoteService_Realtime(t *testing.T) {
 		  }`)
 	})
 
-	quotes, err := client.Quote.Realtime("2330", "3374")
+	quotes, err := client.Quote.Realtime(Instruments("2330", "3374")...)
 	if err != nil {
 		t.Errorf("Quote.Realtime returned error: %v", err)
 	}
 	want := map[string]RealtimeQuote{
 		"2330": {
-			At:       time.Date(2022, 8, 22, 6, 30, 0, 0, time.UTC),
+			At:       time.Date(2022, 8, 22, 14, 30, 0, 0, taipei),
 			Code:     "2330",
 			Name:     "台積電",
 			FullName: "台灣積體電路製造股份有限公司",
@@ -1235,9 +1744,17 @@ func TestQuoteService_Realtime(t *testing.T) {
 				{decimal.NewFromInt(514), 352},
 				{decimal.NewFromInt(515), 434},
 			},
+			HasTrade:      true,
+			PreviousClose: decimal.NewFromInt(519),
+			LimitUp:       decimal.NewFromInt(570),
+			LimitDown:     decimal.NewFromFloat(467.5),
+			Exchange:      TWSE,
+			Change:        decimal.NewFromInt(-9),
+			ChangePercent: decimal.NewFromFloat(-1.73),
+			Session:       SessionClosed,
 		},
 		"3374": {
-			At:       time.Date(2022, 8, 22, 6, 30, 0, 0, time.UTC),
+			At:       time.Date(2022, 8, 22, 14, 30, 0, 0, taipei),
 			Code:     "3374",
 			Name:     "精材",
 			FullName: "精材科技股份有限公司",
@@ -1260,6 +1777,14 @@ func TestQuoteService_Realtime(t *testing.T) {
 				{decimal.NewFromFloat(130), 37},
 				{decimal.NewFromFloat(130.5), 46},
 			},
+			HasTrade:      true,
+			PreviousClose: decimal.NewFromFloat(129.5),
+			LimitUp:       decimal.NewFromInt(142),
+			LimitDown:     decimal.NewFromInt(117),
+			Exchange:      TPEx,
+			Change:        decimal.NewFromInt(-1),
+			ChangePercent: decimal.NewFromFloat(-0.77),
+			Session:       SessionClosed,
 		},
 	}
 	if !cmp.Equal(quotes, want) {
@@ -1267,6 +1792,66 @@ func TestQuoteService_Realtime(t *testing.T) {
 	}
 }
 
+func TestQuoteService_RealtimeOddLot(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(realtimeOddLotQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("ex_ch"), "tse_2330.tw"; got != want {
+			t.Errorf("Request ex_ch = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"msgArray": [
+				{
+					"a": "511.0000_512.0000_",
+					"b": "510.0000_509.0000_",
+					"c": "2330",
+					"ex": "tse",
+					"f": "1549_2210_",
+					"g": "3012_820_",
+					"h": "514.0000",
+					"l": "509.0000",
+					"n": "台積電",
+					"nf": "台灣積體電路製造股份有限公司",
+					"o": "511.0000",
+					"tlong": "1661149800000",
+					"tv": "35",
+					"v": "187324",
+					"y": "519.0000",
+					"z": "510.0000"
+				}
+			],
+			"rtmessage": "OK"
+		}`)
+	})
+
+	quotes, err := client.Quote.RealtimeOddLot(context.Background(), Instruments("2330")...)
+	if err != nil {
+		t.Fatalf("Quote.RealtimeOddLot returned error: %v", err)
+	}
+	quote, ok := quotes["2330"]
+	if !ok {
+		t.Fatalf("Quote.RealtimeOddLot returned %v, want 2330", quotes)
+	}
+	if !quote.OddLot {
+		t.Error("Quote.RealtimeOddLot returned OddLot = false, want true")
+	}
+	if quote.Volume != 187324 || quote.LastTradeVolume != 35 {
+		t.Errorf("Quote.RealtimeOddLot returned volume %d (%d), want 187324 (35)", quote.Volume, quote.LastTradeVolume)
+	}
+	want := []BidAsk{{decimal.NewFromInt(510), 3012}, {decimal.NewFromInt(509), 820}}
+	if !cmp.Equal(quote.Bids, want) {
+		t.Errorf("Quote.RealtimeOddLot returned bids %v, want %v", quote.Bids, want)
+	}
+
+	_, err = client.Quote.RealtimeOddLot(context.Background(), Instruments("BAD")...)
+	if err == nil {
+		t.Error("Quote.RealtimeOddLot returned nil; expected error")
+	}
+}
+
 func TestQuoteService_RealtimeError(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -1276,18 +1861,131 @@ func TestQuoteService_RealtimeError(t *testing.T) {
 		w.WriteHeader(http.StatusBadRequest)
 	})
 
-	_, err := client.Quote.Realtime("2330", "3374")
+	_, err := client.Quote.Realtime(Instruments("2330", "3374")...)
 	if err == nil {
 		t.Error("Quote.Realtime returned nil; expected error")
 	}
 	testErrorContains(t, err, ": 400")
 
-	_, err = client.Quote.Realtime("BAD")
+	_, err = client.Quote.Realtime(Instruments("BAD")...)
 	if err == nil {
 		t.Error("Quote.Realtime returned nil; expected error")
 	}
 }
 
+func TestQuoteService_RealtimeBatch(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.realtimeBatchSize = 2
+
+	requests := []string{}
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		channels := r.URL.Query().Get("ex_ch")
+		requests = append(requests, channels)
+		if channels == "tse_2330.tw|otc_3374.tw" {
+			fmt.Fprint(w, realtimeJSON("2330", 1661149800000, 20813))
+			return
+		}
+		fmt.Fprint(w, realtimeJSON("3049", 1661149800000, 100))
+	})
+
+	quotes, err := client.Quote.Realtime(Instruments("2330", "3374", "3049")...)
+	var codeErrs CodeErrors
+	if !errors.As(err, &codeErrs) {
+		t.Fatalf("Quote.Realtime returned error %v, want CodeErrors", err)
+	}
+	if len(codeErrs) != 1 || !errors.Is(codeErrs["3374"], ErrNoData) {
+		t.Errorf("Quote.Realtime returned %v, want 3374 %v", codeErrs, ErrNoData)
+	}
+	testErrorContains(t, err, "3374: no data found")
+	wantRequests := []string{"tse_2330.tw|otc_3374.tw", "tse_3049.tw"}
+	if !cmp.Equal(requests, wantRequests) {
+		t.Errorf("Quote.Realtime requested %v, want %v", requests, wantRequests)
+	}
+	if len(quotes) != 2 || quotes["2330"].Code != "2330" || quotes["3049"].Code != "3049" {
+		t.Errorf("Quote.Realtime returned %v, want quotes for 2330 and 3049", quotes)
+	}
+}
+
+func TestQuoteService_RealtimeBatchFailed(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	client.realtimeBatchSize = 1
+
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ex_ch") == "tse_2330.tw" {
+			fmt.Fprint(w, realtimeJSON("2330", 1661149800000, 20813))
+			return
+		}
+		fmt.Fprint(w, `{"rtcode": "0000", "rtmessage": "Fail", "stat": "FAIL"}`)
+	})
+
+	quotes, err := client.Quote.Realtime(Instruments("2330", "3374", "3049")...)
+	var codeErrs CodeErrors
+	if !errors.As(err, &codeErrs) {
+		t.Fatalf("Quote.Realtime returned error %v, want CodeErrors", err)
+	}
+	var statErr *StatError
+	if len(codeErrs) != 2 || !errors.As(codeErrs["3374"], &statErr) || !errors.As(codeErrs["3049"], &statErr) {
+		t.Errorf("Quote.Realtime returned %v, want 3374 and 3049 *StatError", codeErrs)
+	}
+	if len(quotes) != 1 || quotes["2330"].Code != "2330" {
+		t.Errorf("Quote.Realtime returned %v, want quote for 2330", quotes)
+	}
+
+	// 所有批次都失敗時直接回傳錯誤
+	quotes, err = client.Quote.Realtime(Instruments("3374", "3049")...)
+	if !errors.As(err, &statErr) || errors.As(err, &codeErrs) {
+		t.Errorf("Quote.Realtime returned %v, want *StatError", err)
+	}
+	if quotes != nil {
+		t.Errorf("Quote.Realtime returned %v, want nil", quotes)
+	}
+}
+
+func TestCodeErrors_Error(t *testing.T) {
+	err := CodeErrors{"3374": ErrNoData, "2330": errors.New("bad price")}
+	want := "failed codes: 2330: bad price; 3374: " + ErrNoData.Error()
+	if got := err.Error(); got != want {
+		t.Errorf("CodeErrors.Error = %v, want %v", got, want)
+	}
+}
+
+func TestQuoteService_RealtimeInstruments(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("ex_ch"), "tse_2330.tw|otc_9999.tw"; got != want {
+			t.Errorf("Request ex_ch = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, realtimeJSON("2330", 1661149800000, 20813))
+	})
+
+	instruments := []Instrument{{Code: "2330"}, {Code: "9999", Market: TPEx}}
+	_, err := client.Quote.Realtime(instruments...)
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Quote.Realtime returned %v, want %v", err, ErrNoData)
+	}
+	// 重複使用相同的 slice 不應該失敗
+	_, err = client.Quote.Realtime(instruments...)
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Quote.Realtime returned %v, want %v", err, ErrNoData)
+	}
+	want := []Instrument{{Code: "2330"}, {Code: "9999", Market: TPEx}}
+	if !cmp.Equal(instruments, want) {
+		t.Errorf("Quote.Realtime modified instruments to %v, want %v", instruments, want)
+	}
+
+	for _, bad := range []Instrument{{Code: "9999"}, {Code: "9999", Market: "BAD"}, {Market: TWSE}} {
+		if _, err := client.Quote.Realtime(bad); err == nil {
+			t.Errorf("Quote.Realtime(%v) returned nil; expected error", bad)
+		}
+	}
+}
+
 func TestQuoteService_RealtimeBadStat(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -1314,7 +2012,7 @@ func TestQuoteService_RealtimeBadStat(t *testing.T) {
 		  }`)
 	})
 
-	_, err := client.Quote.Realtime("2330", "3374")
+	_, err := client.Quote.Realtime(Instruments("2330", "3374")...)
 	if err == nil {
 		t.Error("Quote.Realtime returned nil; expected error")
 	}
@@ -1386,7 +2084,7 @@ func TestQuoteService_RealtimeBadContent(t *testing.T) {
 		  }`)
 	})
 
-	_, err := client.Quote.Realtime("2330", "3374")
+	_, err := client.Quote.Realtime(Instruments("2330", "3374")...)
 	if err == nil {
 		t.Error("Quote.Realtime returned nil; expected error")
 	}
@@ -0,0 +1,130 @@
+package twstock
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Host 代表 Client 存取的網站，每個網站各自套用請求頻率限制
+type Host string
+
+const (
+	TwseHost Host = "twse" // 臺灣證券交易所
+	TpexHost Host = "tpex" // 證券櫃檯買賣中心
+	MisHost  Host = "mis"  // 基本市況報導網站
+	IsinHost Host = "isin" // 國際證券辨識號碼一覽表
+)
+
+// 預設的請求頻率限制，臺灣證券交易所在每五秒超過三次請求時會封鎖 IP
+var defaultRateLimits = map[Host]struct {
+	requests int
+	per      time.Duration
+}{
+	TwseHost: {3, 5 * time.Second},
+	TpexHost: {3, 5 * time.Second},
+	MisHost:  {3, 5 * time.Second},
+	IsinHost: {1, 2 * time.Second},
+}
+
+// rateLimiter spaces requests evenly so that no more than the configured
+// number of requests are sent within any window of the configured duration.
+// It is a token bucket holding a single token, a larger bucket would allow a
+// burst right after an idle period followed by refilled tokens which exceeds
+// the limit of a rolling window.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+// newRateLimiter returns a limiter allowing requests per the given duration,
+// or nil which never blocks when requests or per is not positive.
+func newRateLimiter(requests int, per time.Duration) *rateLimiter {
+	if requests <= 0 || per <= 0 {
+		return nil
+	}
+	// 無條件進位，避免間隔加總後略短於 per
+	return &rateLimiter{
+		interval: (per + time.Duration(requests) - 1) / time.Duration(requests),
+		tokens:   1,
+	}
+}
+
+// reserve takes a token at now and returns how long the request has to wait
+// before it may be sent.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > 1 {
+			l.tokens = 1
+		}
+	}
+	l.last = now
+	// 預約一個 token，不足時等到補滿為止
+	l.tokens--
+	if l.tokens < 0 {
+		return time.Duration(math.Ceil(-l.tokens * float64(l.interval)))
+	}
+	return 0
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := l.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 取消預約，讓出 token 給其他請求
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// WithRateLimit limits requests sent to host to the given number per
+// duration. A non-positive requests or per disables the limit for host.
+func WithRateLimit(host Host, requests int, per time.Duration) ClientOption {
+	return func(c *Client) { c.limiters[host] = newRateLimiter(requests, per) }
+}
+
+// limiter returns the rate limiter of the host serving u, or nil if u does
+// not belong to any known host.
+func (c *Client) limiter(u *url.URL) *rateLimiter {
+	if u == nil {
+		return nil
+	}
+	for _, v := range []struct {
+		host    Host
+		baseURL *url.URL
+	}{
+		{TwseHost, c.twseBaseURL},
+		{TpexHost, c.tpexBaseURL},
+		{MisHost, c.misTwseBaseURL},
+		{IsinHost, c.isinTwseBaseURL},
+	} {
+		if v.baseURL.Host == u.Host {
+			return c.limiters[v.host]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := newRateLimiter(2, 100*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("rateLimiter.wait returned error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("rateLimiter.wait returned after %v, want at least %v", elapsed, 40*time.Millisecond)
+	}
+}
+
+func TestRateLimiter_DefaultTwseWindow(t *testing.T) {
+	limit := defaultRateLimits[TwseHost]
+	l := newRateLimiter(limit.requests, limit.per)
+
+	// 同時送出的請求，以及閒置一段時間後再同時送出的請求
+	start := time.Date(2022, time.August, 22, 9, 0, 0, 0, time.UTC)
+	sent := []time.Time{}
+	for _, at := range []time.Time{start, start.Add(time.Minute)} {
+		for i := 0; i < 2*limit.requests; i++ {
+			sent = append(sent, at.Add(l.reserve(at)))
+		}
+	}
+	for i := limit.requests; i < len(sent); i++ {
+		if window := sent[i].Sub(sent[i-limit.requests]); window < limit.per {
+			t.Errorf("request %d sent %v after request %d, want at most %d requests per %v", i, window, i-limit.requests, limit.requests, limit.per)
+		}
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := newRateLimiter(1, time.Hour)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("rateLimiter.wait returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("rateLimiter.wait returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if l.tokens < 0 {
+		t.Errorf("rateLimiter.tokens = %v after canceled wait, want reservation returned", l.tokens)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("rateLimiter.wait returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	for _, l := range []*rateLimiter{newRateLimiter(0, time.Second), newRateLimiter(1, 0)} {
+		if l != nil {
+			t.Errorf("newRateLimiter returned %v, want nil", l)
+		}
+		if err := l.wait(context.Background()); err != nil {
+			t.Errorf("rateLimiter.wait returned error: %v", err)
+		}
+	}
+}
+
+func TestClient_LimiterPerHost(t *testing.T) {
+	twse, _ := url.Parse("http://twse.test/")
+	tpex, _ := url.Parse("http://tpex.test/")
+	mis, _ := url.Parse("http://mis.test/")
+	isin, _ := url.Parse("http://isin.test/")
+	c := NewClient(
+		WithTwseBaseURL(twse),
+		WithTpexBaseURL(tpex),
+		WithMisBaseURL(mis),
+		WithIsinBaseURL(isin),
+	)
+	for host, u := range map[Host]*url.URL{TwseHost: twse, TpexHost: tpex, MisHost: mis, IsinHost: isin} {
+		if got := c.limiter(u); got == nil || got != c.limiters[host] {
+			t.Errorf("Client.limiter(%v) = %v, want %v", u, got, c.limiters[host])
+		}
+	}
+	other, _ := url.Parse("http://other.test/")
+	if got := c.limiter(other); got != nil {
+		t.Errorf("Client.limiter(%v) = %v, want nil", other, got)
+	}
+	if got := c.limiter(nil); got != nil {
+		t.Errorf("Client.limiter(nil) = %v, want nil", got)
+	}
+}
+
+func TestDo_RateLimited(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	WithRateLimit(TwseHost, 1, time.Hour)(client)
+
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("client.Do returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.DoWithContext(ctx, req, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("client.DoWithContext returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := client.DoTransformToDocumentWithContext(ctx, req, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("client.DoTransformToDocumentWithContext returned %v, want %v", err, context.DeadlineExceeded)
+	}
+}
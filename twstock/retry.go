@@ -0,0 +1,172 @@
+package twstock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// 網站封鎖 IP 但沒有提供 Retry-After 時建議的等待時間
+const defaultBanWait = 10 * time.Minute
+
+// 當網站限制請求頻率或封鎖 IP 時丟出此錯誤，實際的錯誤型別為 *RateLimitError
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError occurs when the server rejects requests because of too many
+// requests, or returns a ban page instead of the expected JSON.
+type RateLimitError struct {
+	Response   *http.Response // HTTP response that caused this error
+	RetryAfter time.Duration  // 建議等待多久後再重新請求
+}
+
+func (r *RateLimitError) Error() string {
+	return fmt.Sprintf("%v %v: %d rate limited, retry after %v",
+		r.Response.Request.Method, r.Response.Request.URL,
+		r.Response.StatusCode, r.RetryAfter)
+}
+
+// Is reports whether target is ErrRateLimited.
+func (r *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryPolicy 設定暫時性錯誤（連線錯誤及 5xx 回應）的重試策略，
+// 每次重試前的等待時間會以指數成長並加上隨機抖動
+type RetryPolicy struct {
+	MaxRetries int           // 最多重試次數，0 表示不重試
+	MinBackoff time.Duration // 第一次重試前的等待時間
+	MaxBackoff time.Duration // 等待時間上限
+}
+
+// DefaultRetryPolicy is the retry policy used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Second,
+	MaxBackoff: 30 * time.Second,
+}
+
+// WithRetryPolicy sets the policy used to retry transient failures.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) { c.retryPolicy = p }
+}
+
+// backoff returns the jittered wait before the given retry, counting from 0.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MaxBackoff
+	if retry < 32 && p.MinBackoff<<retry < p.MaxBackoff {
+		d = p.MinBackoff << retry
+	}
+	if d <= 0 {
+		return 0
+	}
+	// 等待時間介於 d/2 到 d 之間，避免多個請求同時重試
+	return d/2 + rand.N(d/2+1) //nolint:gosec
+}
+
+// isRetryable reports whether err returned by http.Client.Do is a transient
+// network failure that is worth retrying, permanent failures such as an
+// unknown host are returned immediately.
+func isRetryable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound && (dnsErr.IsTimeout || dnsErr.IsTemporary)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// parseRetryAfter parses the Retry-After header which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return defaultBanWait
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultBanWait
+}
+
+// isHTML reports whether the body starts with a HTML tag, skipping leading
+// white space which a JSON decoder ignores anyway.
+func isHTML(r *bufio.Reader) bool {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return false
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		_ = r.UnreadByte()
+		return b == '<'
+	}
+}
+
+// send sends an API request honoring the rate limit and retry policy. The
+// caller must close the body of the returned response.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for retry := 0; ; retry++ {
+		if err := c.limiter(req.URL).wait(ctx); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if retry > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := c.client.Do(r) //nolint:gosec
+		if err != nil {
+			err = contextError(req, err)
+			if ctx.Err() != nil || !isRetryable(err) || retry >= c.retryPolicy.MaxRetries {
+				return nil, err
+			}
+		} else if resp.StatusCode == http.StatusTooManyRequests {
+			return resp, &RateLimitError{Response: resp, RetryAfter: parseRetryAfter(resp.Header)}
+		} else if err = CheckResponse(resp); err != nil {
+			if resp.StatusCode < 500 || retry >= c.retryPolicy.MaxRetries {
+				return resp, err
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			return resp, nil
+		}
+
+		timer := time.NewTimer(c.retryPolicy.backoff(retry))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package twstock
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDo_RetryServerError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "a=1" {
+			t.Errorf("Request body = %q, want %q", body, "a=1")
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "OK"}`)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("POST", u.String(), "a=1")
+	resp := &twseResponse{}
+	if _, err := client.Do(req, resp); err != nil {
+		t.Fatalf("client.Do returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("client.Do sent %d requests, want 3", calls)
+	}
+	if resp.Stat != "OK" {
+		t.Errorf("client.Do decoded stat %q, want %q", resp.Stat, "OK")
+	}
+}
+
+func TestDo_RetryExhausted(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	_, err := client.Do(req, nil)
+	if err == nil {
+		t.Fatal("client.Do returned nil; expected error")
+	}
+	testErrorContains(t, err, ": 500")
+	if calls != 3 {
+		t.Errorf("client.Do sent %d requests, want 3", calls)
+	}
+}
+
+func TestDo_NoRetryClientError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	if _, err := client.Do(req, nil); err == nil {
+		t.Fatal("client.Do returned nil; expected error")
+	}
+	if calls != 1 {
+		t.Errorf("client.Do sent %d requests, want 1", calls)
+	}
+}
+
+func TestDo_RetryTransportError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatalf("Hijack returned error: %v", err)
+			}
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("client.Do returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("client.Do sent %d requests, want 2", calls)
+	}
+}
+
+func TestDo_TooManyRequests(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	_, err := client.Do(req, nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("client.Do returned %v, want %v", err, ErrRateLimited)
+	}
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 120*time.Second {
+		t.Errorf("client.Do returned %v, want retry after %v", err, 120*time.Second)
+	}
+	testErrorContains(t, err, ": 429")
+	if calls != 1 {
+		t.Errorf("client.Do sent %d requests, want 1", calls)
+	}
+
+	_, err = client.DoTransformToDocument(req, nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("client.DoTransformToDocument returned %v, want %v", err, ErrRateLimited)
+	}
+}
+
+func TestDo_BanPage(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+		<html>
+			<body>因為安全性考量，您的 IP 已被暫時封鎖</body>
+		</html>`)
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, _ := client.NewRequest("GET", u.String(), nil)
+	_, err := client.Do(req, &twseResponse{})
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != defaultBanWait {
+		t.Errorf("client.Do returned %v, want retry after %v", err, defaultBanWait)
+	}
+
+	// 不需要解析內容時不檢查是否為 HTML
+	if _, err := client.Do(req, nil); err != nil {
+		t.Errorf("client.Do returned error: %v", err)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	var testCases = []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond, 800 * time.Millisecond},
+		{4, 500 * time.Millisecond, time.Second},
+		{64, 500 * time.Millisecond, time.Second},
+	}
+	for _, test := range testCases {
+		t.Run(fmt.Sprint(test.retry), func(t *testing.T) {
+			if d := p.backoff(test.retry); d < test.min || d > test.max {
+				t.Errorf("RetryPolicy.backoff(%d) = %v, want between %v and %v", test.retry, d, test.min, test.max)
+			}
+		})
+	}
+
+	if d := (RetryPolicy{}).backoff(0); d != 0 {
+		t.Errorf("RetryPolicy.backoff(0) = %v, want 0", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	var testCases = map[string]struct {
+		value string
+		want  time.Duration
+	}{
+		"empty":   {"", defaultBanWait},
+		"seconds": {"30", 30 * time.Second},
+		"past":    {"Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		"invalid": {"soon", defaultBanWait},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("Retry-After", test.value)
+			if got := parseRetryAfter(h); got != test.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+
+	h := http.Header{}
+	h.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if got := parseRetryAfter(h); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter returned %v, want about %v", got, time.Hour)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	var testCases = map[string]struct {
+		err  error
+		want bool
+	}{
+		"eof":        {&url.Error{Op: "Get", URL: "/", Err: io.EOF}, true},
+		"reset":      {&url.Error{Op: "Get", URL: "/", Err: syscall.ECONNRESET}, true},
+		"unexpected": {io.ErrUnexpectedEOF, true},
+		"permanent":  {&url.Error{Op: "Get", URL: "/", Err: errors.New("unsupported protocol scheme")}, false},
+		"refused":    {&url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, true},
+		"timeout":    {&url.Error{Op: "Get", URL: "/", Err: &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}}, true},
+		"no host":    {&url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}}}, false},
+		"net error":  {&url.Error{Op: "Get", URL: "/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: network is unreachable")}}, false},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := isRetryable(test.err); got != test.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+)
+
+type SBLService struct {
+	client *Client
+}
+
+const (
+	// 上市融券借券賣出餘額
+	twseSBLPath = "/rwd/zh/marginTrading/TWT93U"
+
+	// 上櫃融券借券賣出餘額
+	tpexSBLPath = "/www/zh-tw/margin/sbl"
+)
+
+// 個股融券及借券賣出餘額，單位為股
+type ShortSale struct {
+	Date civil.Date // 日期
+	Code string     // 股票代號
+	Name string     // 股票名稱
+
+	ShortPreviousBalance int // 融券前日餘額
+	ShortSell            int // 融券賣出
+	ShortCover           int // 融券買進
+	ShortRedemption      int // 現券償還
+	ShortBalance         int // 融券今日餘額
+	ShortLimit           int // 融券限額
+
+	SBLPreviousBalance int // 借券賣出前日餘額
+	SBLSell            int // 借券當日賣出
+	SBLReturn          int // 借券當日還券
+	SBLAdjustment      int // 借券當日調整
+	SBLBalance         int // 借券賣出當日餘額
+	SBLLimit           int // 次一營業日借券賣出可限額
+
+	Note      string // 備註
+	Suspended bool   // 暫停融券賣出，由備註中的 X 判斷
+}
+
+// 備註中表示暫停融券賣出的註記
+const shortSaleSuspendedMarker = "X"
+
+// 融券借券賣出餘額的欄位數，上市及上櫃的欄位順序相同
+const sblFieldsLength = 15
+
+// volumes returns pointers to the share fields in the order of the columns
+// of the report.
+func (s *ShortSale) volumes() []*int {
+	return []*int{
+		&s.ShortPreviousBalance, &s.ShortSell, &s.ShortCover, &s.ShortRedemption, &s.ShortBalance, &s.ShortLimit,
+		&s.SBLPreviousBalance, &s.SBLSell, &s.SBLReturn, &s.SBLAdjustment, &s.SBLBalance, &s.SBLLimit,
+	}
+}
+
+// checkSBLFields validates the headers of the report, the short sale and SBL
+// groups share the same names so only the distinct ones are checked.
+func checkSBLFields(fields []string) error {
+	trimmed := make([]string, len(fields))
+	for i, v := range fields {
+		trimmed[i] = strings.TrimSpace(v)
+	}
+	if len(trimmed) != sblFieldsLength ||
+		trimmed[0] != "代號" ||
+		trimmed[1] != "名稱" ||
+		trimmed[2] != "前日餘額" ||
+		trimmed[3] != "賣出" ||
+		trimmed[4] != "買進" ||
+		trimmed[8] != "前日餘額" ||
+		trimmed[9] != "當日賣出" ||
+		trimmed[10] != "當日還券" ||
+		trimmed[11] != "當日調整" ||
+		trimmed[12] != "當日餘額" ||
+		trimmed[14] != "備註" {
+		return fmt.Errorf("failed parsing short sale fields: %s", strings.Join(trimmed, ","))
+	}
+	return nil
+}
+
+// isSBLTotalRow reports whether data is the total row at the end of the
+// report.
+func isSBLTotalRow(data []string) bool {
+	return len(data) > 0 && strings.TrimSpace(data[0]) == "合計"
+}
+
+func (*SBLService) parse(date civil.Date, data []string) (ShortSale, error) {
+	sale := ShortSale{Date: date}
+	if len(data) != sblFieldsLength {
+		return sale, fmt.Errorf("failed parsing short sale fields")
+	}
+	sale.Code = strings.TrimSpace(data[0])
+	sale.Name = strings.TrimSpace(data[1])
+	for i, v := range sale.volumes() {
+		volume, err := parseVolume(strings.TrimSpace(data[i+2]))
+		if err != nil {
+			return sale, fmt.Errorf("failed parsing short sale %s: %w", sale.Code, err)
+		}
+		*v = volume
+	}
+	sale.Note = strings.TrimSpace(data[14])
+	sale.Suspended = strings.Contains(sale.Note, shortSaleSuspendedMarker)
+	return sale, nil
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *SBLService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所融券借券賣出餘額最早到民國94年7月
+		return civil.Date{Year: 2005, Month: time.July, Day: 1}
+	}
+	// 證券櫃檯買賣中心融券借券賣出餘額最早到民國96年1月
+	return civil.Date{Year: 2007, Month: time.January, Day: 1}
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的融券及借券賣出餘額
+func (s *SBLService) DownloadTwse(ctx context.Context, date civil.Date) (map[string]ShortSale, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseSBLPath)
+	opts := twseOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if err := checkSBLFields(resp.Fields); err != nil {
+		return nil, err
+	}
+	sales := map[string]ShortSale{}
+	for _, data := range resp.Data {
+		if isSBLTotalRow(data) {
+			continue
+		}
+		sale, err := s.parse(date, data)
+		if err != nil {
+			return nil, err
+		}
+		sales[sale.Code] = sale
+	}
+	return sales, nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的融券及借券賣出餘額
+func (s *SBLService) DownloadTpex(ctx context.Context, date civil.Date) (map[string]ShortSale, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexSBLPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	if err := checkSBLFields(table.Fields); err != nil {
+		return nil, err
+	}
+	date, err = tpexTableDate(date, table.Date)
+	if err != nil {
+		return nil, err
+	}
+	sales := map[string]ShortSale{}
+	for _, row := range table.Data {
+		data := tpexStrings(row)
+		if isSBLTotalRow(data) {
+			continue
+		}
+		sale, err := s.parse(date, data)
+		if err != nil {
+			return nil, err
+		}
+		sales[sale.Code] = sale
+	}
+	return sales, nil
+}
+
+// 從台灣證卷交易所及證券櫃檯買賣中心下載指定日期暫停融券賣出的股票代號，依代號排序。
+// 指定日期早於證券櫃檯買賣中心的最小查詢日期時只查詢上市個股
+func (s *SBLService) DownloadSuspended(ctx context.Context, date civil.Date) ([]string, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	codes := []string{}
+	for _, m := range []struct {
+		market   Market
+		download func(context.Context, civil.Date) (map[string]ShortSale, error)
+	}{
+		{TWSE, s.DownloadTwse},
+		{TPEx, s.DownloadTpex},
+	} {
+		if date.Before(s.MinimumDate(m.market)) {
+			continue
+		}
+		sales, err := m.download(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		for code, sale := range sales {
+			if sale.Suspended {
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
@@ -0,0 +1,321 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSBLFields = `["代號", "名稱", "前日餘額", "賣出", "買進", "現券", "今日餘額", "次一營業日限額", "前日餘額", "當日賣出", "當日還券", "當日調整", "當日餘額", "次一營業日可限額", "備註"]`
+
+func TestSBLService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"title": "111年08月22日 融券借券賣出餘額",
+			"fields": `+testSBLFields+`,
+			"data": [
+				["2330", "台積電", "3,024,000", "312,000", "24,000", "3,000", "3,309,000", "6,483,073,000", "26,105,337", "1,115,000", "301,000", "0", "26,919,337", "1,602,345", ""],
+				["2498", "宏達電", "1,207,000", "50,000", "25,000", "0", "1,232,000", "206,013,000", "3,002,000", "0", "0", "0", "3,002,000", "301,000", "X"],
+				["合計", "", "", "", "", "", "", "", "", "", "", "", "", "", ""]
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	sales, err := client.SBL.DownloadTwse(context.Background(), date)
+	if err != nil {
+		t.Fatalf("SBL.DownloadTwse returned error: %v", err)
+	}
+	want := map[string]ShortSale{
+		"2330": {
+			Date:                 date,
+			Code:                 "2330",
+			Name:                 "台積電",
+			ShortPreviousBalance: 3024000,
+			ShortSell:            312000,
+			ShortCover:           24000,
+			ShortRedemption:      3000,
+			ShortBalance:         3309000,
+			ShortLimit:           6483073000,
+			SBLPreviousBalance:   26105337,
+			SBLSell:              1115000,
+			SBLReturn:            301000,
+			SBLBalance:           26919337,
+			SBLLimit:             1602345,
+		},
+		"2498": {
+			Date:                 date,
+			Code:                 "2498",
+			Name:                 "宏達電",
+			ShortPreviousBalance: 1207000,
+			ShortSell:            50000,
+			ShortCover:           25000,
+			ShortBalance:         1232000,
+			ShortLimit:           206013000,
+			SBLPreviousBalance:   3002000,
+			SBLBalance:           3002000,
+			SBLLimit:             301000,
+			Note:                 "X",
+			Suspended:            true,
+		},
+	}
+	if !cmp.Equal(sales, want) {
+		t.Errorf("SBL.DownloadTwse returned %+v, want %+v", sales, want)
+	}
+}
+
+func TestSBLService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.SBL.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("SBL.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestSBLService_DownloadTwseBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["代號", "名稱"], "data": []}`)
+	})
+
+	_, err := client.SBL.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("SBL.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing short sale fields: 代號,名稱")
+}
+
+func TestSBLService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testSBLFields+`, "data": [["2330", "台積電", "1"]]}`)
+	})
+
+	_, err := client.SBL.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("SBL.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing short sale fields")
+}
+
+func TestSBLService_DownloadTwseBadData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testSBLFields+`, "data": [["2330", "台積電", "BAD", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", ""]]}`)
+	})
+
+	_, err := client.SBL.DownloadTwse(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("SBL.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing short sale 2330")
+}
+
+func TestSBLService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.SBL.DownloadTwse(context.Background(), civil.Date{Year: 2005, Month: time.June, Day: 30})
+	if err == nil {
+		t.Fatal("SBL.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2005-06-30")
+}
+
+func TestSBLService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"fields": `+testSBLFields+`,
+					"data": [
+						["3374", "精材", "160,000", "31,000", "17,000", "0", "174,000", "67,920,000", "52,000", 0, "0", "0", "52,000", "1,380,000", " "],
+						["合計", "", "", "", "", "", "", "", "", "", "", "", "", "", ""]
+					],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	sales, err := client.SBL.DownloadTpex(context.Background(), date)
+	if err != nil {
+		t.Fatalf("SBL.DownloadTpex returned error: %v", err)
+	}
+	want := map[string]ShortSale{
+		"3374": {
+			Date:                 date,
+			Code:                 "3374",
+			Name:                 "精材",
+			ShortPreviousBalance: 160000,
+			ShortSell:            31000,
+			ShortCover:           17000,
+			ShortBalance:         174000,
+			ShortLimit:           67920000,
+			SBLPreviousBalance:   52000,
+			SBLBalance:           52000,
+			SBLLimit:             1380000,
+		},
+	}
+	if !cmp.Equal(sales, want) {
+		t.Errorf("SBL.DownloadTpex returned %+v, want %+v", sales, want)
+	}
+}
+
+func TestSBLService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.SBL.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("SBL.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestSBLService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.SBL.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("SBL.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestSBLService_DownloadTpexBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.SBL.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("SBL.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing short sale fields: 代號")
+}
+
+func TestSBLService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testSBLFields+`, "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.SBL.DownloadTpex(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("SBL.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing short sale fields")
+}
+
+func TestSBLService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.SBL.DownloadTpex(context.Background(), civil.Date{Year: 2006, Month: time.December, Day: 29})
+	if err == nil {
+		t.Fatal("SBL.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2006-12-29")
+}
+
+func TestSBLService_DownloadSuspended(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	row := func(code, note string) string {
+		return `["` + code + `", "名稱"` + strings.Repeat(`, "0"`, 12) + `, "` + note + `"]`
+	}
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testSBLFields+`, "data": [`+row("2498", "X")+`, `+row("2330", "")+`, `+row("1101", "X")+`]}`)
+	})
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": `+testSBLFields+`, "data": [`+row("3374", "X")+`], "totalCount": 1}]}`)
+	})
+
+	codes, err := client.SBL.DownloadSuspended(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err != nil {
+		t.Fatalf("SBL.DownloadSuspended returned error: %v", err)
+	}
+	if want := []string{"1101", "2498", "3374"}; !cmp.Equal(codes, want) {
+		t.Errorf("SBL.DownloadSuspended returned %v, want %v", codes, want)
+	}
+
+	_, err = client.SBL.DownloadSuspended(context.Background(), civil.Date{Year: 2005, Month: time.June, Day: 30})
+	if err == nil {
+		t.Fatal("SBL.DownloadSuspended returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2005-06-30")
+}
+
+func TestSBLService_DownloadSuspendedBeforeTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": `+testSBLFields+`, "data": [["2498", "宏達電"`+strings.Repeat(`, "0"`, 12)+`, "X"]]}`)
+	})
+	mux.HandleFunc(tpexSBLPath, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("SBL.DownloadSuspended requested %v, want no TPEx request", r.URL)
+	})
+
+	codes, err := client.SBL.DownloadSuspended(context.Background(), civil.Date{Year: 2006, Month: time.June, Day: 1})
+	if err != nil {
+		t.Fatalf("SBL.DownloadSuspended returned error: %v", err)
+	}
+	if want := []string{"2498"}; !cmp.Equal(codes, want) {
+		t.Errorf("SBL.DownloadSuspended returned %v, want %v", codes, want)
+	}
+}
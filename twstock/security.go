@@ -1,6 +1,7 @@
 package twstock
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -41,8 +42,8 @@ type DelistedSecurity struct {
 	Market Market // 市場別
 }
 
-func (s *SecurityService) download(url string, t transform.Transformer) ([]Security, error) {
-	req, err := s.client.NewRequest("GET", url, nil)
+func (s *SecurityService) download(ctx context.Context, url string, t transform.Transformer) ([]Security, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -113,10 +114,18 @@ const (
 
 // 從台灣證卷交易所下載上市及上櫃國際證券資料
 func (s *SecurityService) Download() ([]Security, error) {
+	return s.DownloadWithContext(context.Background())
+}
+
+// 從台灣證卷交易所下載上市及上櫃國際證券資料，可透過 ctx 取消請求
+func (s *SecurityService) DownloadWithContext(ctx context.Context) ([]Security, error) {
 	securities := []Security{}
 	for _, path := range []string{twseSecuritiesPath, tpexSecuritiesPath} {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		url, _ := s.client.isinTwseBaseURL.Parse(path)
-		s, err := s.download(url.String(), s.client.isinTwseDecoder)
+		s, err := s.download(ctx, url.String(), s.client.isinTwseDecoder)
 		if err != nil {
 			return nil, err
 		}
@@ -127,8 +136,13 @@ func (s *SecurityService) Download() ([]Security, error) {
 
 // 從台灣證卷交易所下載下市的國際證券資料
 func (s *SecurityService) DownloadTwseDelisted() ([]DelistedSecurity, error) {
+	return s.DownloadTwseDelistedWithContext(context.Background())
+}
+
+// 從台灣證卷交易所下載下市的國際證券資料，可透過 ctx 取消請求
+func (s *SecurityService) DownloadTwseDelistedWithContext(ctx context.Context) ([]DelistedSecurity, error) {
 	url, _ := s.client.twseBaseURL.Parse(twseDelistedSecuritiesPath)
-	req, _ := s.client.NewRequest("POST", url.String(), "maxLength=-1&selectYear=&submitBtn=%E6%9F%A5%E8%A9%A2")
+	req, _ := s.client.NewRequestWithContext(ctx, "POST", url.String(), "maxLength=-1&selectYear=&submitBtn=%E6%9F%A5%E8%A9%A2")
 	doc, err := s.client.DoTransformToDocument(req, s.client.twseDecoder)
 	if err != nil {
 		return nil, err
@@ -153,8 +167,13 @@ func (s *SecurityService) DownloadTwseDelisted() ([]DelistedSecurity, error) {
 
 // 從證券櫃檯買賣中心下載下櫃的國際證券資料
 func (s *SecurityService) DownloadTpexDelisted(page int) ([]DelistedSecurity, error) {
+	return s.DownloadTpexDelistedWithContext(context.Background(), page)
+}
+
+// 從證券櫃檯買賣中心下載下櫃的國際證券資料，可透過 ctx 取消請求
+func (s *SecurityService) DownloadTpexDelistedWithContext(ctx context.Context, page int) ([]DelistedSecurity, error) {
 	url, _ := s.client.tpexBaseURL.Parse(tpexDelistedSecuritiesPath)
-	req, _ := s.client.NewRequest("POST", url.String(), fmt.Sprintf("stk_code=&select_year=ALL&topage=%d&DELIST_REASON=-1", page+1))
+	req, _ := s.client.NewRequestWithContext(ctx, "POST", url.String(), fmt.Sprintf("stk_code=&select_year=ALL&topage=%d&DELIST_REASON=-1", page+1))
 	doc, err := s.client.DoTransformToDocument(req, s.client.twseDecoder)
 	if err != nil {
 		return nil, err
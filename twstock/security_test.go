@@ -1,6 +1,8 @@
 package twstock
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -235,7 +237,7 @@ func TestSecurityService_DownloadError(t *testing.T) {
 	}
 	testErrorContains(t, err, ": 400")
 
-	_, err = client.Security.download("\n", nil)
+	_, err = client.Security.download(context.Background(), "\n", nil)
 	if err == nil {
 		t.Error("Security.download returned nil; expected error")
 	}
@@ -248,6 +250,22 @@ func TestSecurityService_DownloadError(t *testing.T) {
 	}
 }
 
+func TestSecurityService_DownloadWithContextCanceled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/isin/C_public.jsp", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Security.DownloadWithContext sent a request with a canceled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Security.DownloadWithContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Security.DownloadWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestSecurityService_DownloadTwseDelisted(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -0,0 +1,120 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// 基本市況報導網站建議的查詢間隔
+	defaultSubscribeInterval = 5 * time.Second
+
+	// 查詢連續失敗時重試間隔的上限
+	maxSubscribeBackoff = time.Minute
+)
+
+// RealtimeUpdate 即時行情訂閱的更新，Err 不為 nil 時代表該次查詢失敗
+type RealtimeUpdate struct {
+	Quote RealtimeQuote // 有變動的即時個股成交資訊
+	Err   error         // 查詢失敗的原因，訂閱會在稍後自動重試
+}
+
+// 定期從台灣證卷交易所查詢即時個股成交資訊，只有在成交時間或總成交量改變時才送出更新。
+// 查詢失敗時會送出錯誤並拉長間隔重試，ctx 取消後會關閉回傳的 channel。
+// 代號無效或無法判斷市場別時不會開始查詢，只送出一次錯誤後關閉 channel
+func (s *QuoteService) Subscribe(ctx context.Context, instruments []Instrument, interval time.Duration) <-chan RealtimeUpdate {
+	if interval <= 0 {
+		interval = defaultSubscribeInterval
+	}
+	instruments = append([]Instrument(nil), instruments...)
+	updates := make(chan RealtimeUpdate)
+	send := func(update RealtimeUpdate) bool {
+		select {
+		case updates <- update:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, instrument := range instruments {
+		if _, err := instrument.channel(); err != nil {
+			go func() {
+				defer close(updates)
+				send(RealtimeUpdate{Err: err})
+			}()
+			return updates
+		}
+	}
+
+	go func() {
+		defer close(updates)
+
+		last := map[string]RealtimeQuote{}
+		failures := 0
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+
+			wait := interval
+			quotes, err := s.RealtimeWithContext(ctx, instruments...)
+			var codeErrs CodeErrors
+			if err != nil && !errors.As(err, &codeErrs) {
+				if ctx.Err() != nil {
+					return
+				}
+				failures++
+				wait = subscribeBackoff(interval, failures)
+				var rateLimitErr *RateLimitError
+				if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > wait {
+					wait = rateLimitErr.RetryAfter
+				}
+				if !send(RealtimeUpdate{Err: err}) {
+					return
+				}
+			} else {
+				failures = 0
+				// 部分代號失敗時仍送出其餘代號的更新
+				if codeErrs != nil && !send(RealtimeUpdate{Err: codeErrs}) {
+					return
+				}
+				for _, instrument := range instruments {
+					code := instrument.Code
+					quote, ok := quotes[code]
+					if !ok {
+						continue
+					}
+					if prev, ok := last[code]; ok && prev.At.Equal(quote.At) && prev.Volume == quote.Volume {
+						continue
+					}
+					last[code] = quote
+					if !send(RealtimeUpdate{Quote: quote}) {
+						return
+					}
+				}
+			}
+			timer.Reset(wait)
+		}
+	}()
+
+	return updates
+}
+
+// subscribeBackoff doubles the interval for each consecutive failure up to
+// maxSubscribeBackoff.
+func subscribeBackoff(interval time.Duration, failures int) time.Duration {
+	wait := interval
+	for i := 0; i < failures && wait < maxSubscribeBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxSubscribeBackoff {
+		return maxSubscribeBackoff
+	}
+	return wait
+}
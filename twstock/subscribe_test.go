@@ -0,0 +1,126 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func realtimeJSON(code string, tlong int64, volume int) string {
+	return fmt.Sprintf(`{
+		"msgArray": [{
+			"a": "511.0000_", "b": "510.0000_", "f": "1_", "g": "1_",
+			"c": "%s", "n": "台積電", "nf": "台灣積體電路製造股份有限公司",
+			"tlong": "%d", "z": "510.0000", "o": "511.0000", "h": "514.0000", "l": "510.0000",
+			"v": "%d"
+		}],
+		"rtmessage": "OK"
+	}`, code, tlong, volume)
+}
+
+func TestQuoteService_Subscribe(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	calls := 0
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// 每兩次查詢才有一筆新的成交
+		fmt.Fprint(w, realtimeJSON("2330", 1661149800000+int64(n/2)*5000, 20813+n/2))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	instruments := Instruments("2330")
+	updates := client.Quote.Subscribe(ctx, instruments, time.Millisecond)
+
+	first := <-updates
+	if first.Err != nil || first.Quote.Volume != 20813 {
+		t.Fatalf("Quote.Subscribe sent %+v, want volume 20813", first)
+	}
+	second := <-updates
+	if second.Err == nil {
+		t.Fatalf("Quote.Subscribe sent %+v, want error", second)
+	}
+	testErrorContains(t, second.Err, ": 400")
+	for _, want := range []int{20814, 20815, 20816} {
+		update := <-updates
+		if update.Err != nil || update.Quote.Volume != want {
+			t.Fatalf("Quote.Subscribe sent %+v, want volume %d", update, want)
+		}
+	}
+	if want := Instruments("2330"); !cmp.Equal(instruments, want) {
+		t.Errorf("Quote.Subscribe modified instruments to %v, want %v", instruments, want)
+	}
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestQuoteService_SubscribeCanceled(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	updates := client.Quote.Subscribe(ctx, Instruments("2330"), 0)
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("Quote.Subscribe sent an update after ctx was canceled")
+		}
+	case <-time.After(time.Second):
+		t.Error("Quote.Subscribe did not close the channel after ctx was canceled")
+	}
+}
+
+func TestQuoteService_SubscribeInvalidInstrument(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(realtimeQuotesPath, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Quote.Subscribe requested %v, want no request", r.URL)
+	})
+
+	updates := client.Quote.Subscribe(context.Background(), Instruments("2330", "BAD"), time.Millisecond)
+	update, ok := <-updates
+	if !ok || update.Err == nil {
+		t.Fatalf("Quote.Subscribe sent %+v, want error", update)
+	}
+	testErrorContains(t, update.Err, "invalid code: BAD")
+	if update, ok := <-updates; ok {
+		t.Errorf("Quote.Subscribe sent %+v, want closed channel", update)
+	}
+}
+
+func TestSubscribeBackoff(t *testing.T) {
+	var testCases = []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{10, maxSubscribeBackoff},
+	}
+	for _, test := range testCases {
+		if got := subscribeBackoff(time.Second, test.failures); got != test.want {
+			t.Errorf("subscribeBackoff(%v, %d) = %v, want %v", time.Second, test.failures, got, test.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package twstock
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,6 +24,9 @@ const (
 	defaultTpexBaseURL     = "https://www.tpex.org.tw"
 	defaultMisTwseBaseURL  = "https://mis.twse.com.tw/"
 	defaultIsinTwseBaseURL = "https://isin.twse.com.tw"
+
+	// 基本市況報導網站單次查詢過多代號時會拒絕或截斷結果
+	defaultRealtimeBatchSize = 50
 )
 
 // A Client manages communication with the API.
@@ -40,10 +45,28 @@ type Client struct {
 	isinTwseBaseURL *url.URL
 	isinTwseDecoder transform.Transformer
 
+	// User agent used when communicating with the API.
+	userAgent string
+
+	// Rate limiters applied separately to each host.
+	limiters map[Host]*rateLimiter
+
+	// Policy used to retry transient failures.
+	retryPolicy RetryPolicy
+
+	// Maximum number of channels in a single realtime request.
+	realtimeBatchSize int
+
 	// Services used for talking to different parts of the API.
-	MarketData *MarketDataService
-	Security   *SecurityService
-	Quote      *QuoteService
+	MarketData     *MarketDataService
+	Security       *SecurityService
+	Quote          *QuoteService
+	Valuation      *ValuationService
+	Institutional  *InstitutionalService
+	Margin         *MarginService
+	SBL            *SBLService
+	ForeignHolding *ForeignHoldingService
+	DayTrading     *DayTradingService
 }
 
 // addOptions adds the parameters in opts as URL query parameters to s. opts
@@ -63,8 +86,107 @@ func addOptions(u *url.URL, opts interface{}) (*url.URL, error) {
 	return u, nil
 }
 
-// NewClient returns a new twstock API client.
-func NewClient() *Client {
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to communicate with the API.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithTwseBaseURL sets the base URL of the Taiwan Stock Exchange website. A nil
+// URL keeps the default, as do the other base URL options.
+func WithTwseBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.twseBaseURL = cloneURL(u)
+		}
+	}
+}
+
+// WithTpexBaseURL sets the base URL of the Taipei Exchange website.
+func WithTpexBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.tpexBaseURL = cloneURL(u)
+		}
+	}
+}
+
+// WithMisBaseURL sets the base URL of the TWSE market information system
+// which serves realtime quotes.
+func WithMisBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.misTwseBaseURL = cloneURL(u)
+		}
+	}
+}
+
+// WithIsinBaseURL sets the base URL of the TWSE ISIN website which serves
+// the list of securities.
+func WithIsinBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.isinTwseBaseURL = cloneURL(u)
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithTwseDecoder sets the decoder used for documents from the Taiwan Stock
+// Exchange website.
+func WithTwseDecoder(t transform.Transformer) ClientOption {
+	return func(c *Client) {
+		if t != nil {
+			c.twseDecoder = t
+		}
+	}
+}
+
+// WithTpexDecoder sets the decoder used for documents from the Taipei
+// Exchange website.
+func WithTpexDecoder(t transform.Transformer) ClientOption {
+	return func(c *Client) {
+		if t != nil {
+			c.tpexDecoder = t
+		}
+	}
+}
+
+// WithIsinDecoder sets the decoder used for documents from the TWSE ISIN
+// website, which are Big5 encoded by default.
+func WithIsinDecoder(t transform.Transformer) ClientOption {
+	return func(c *Client) {
+		if t != nil {
+			c.isinTwseDecoder = t
+		}
+	}
+}
+
+// WithRealtimeBatchSize sets the maximum number of codes sent in a single
+// realtime request, larger lists are split into several requests.
+func WithRealtimeBatchSize(n int) ClientOption {
+	return func(c *Client) { c.realtimeBatchSize = n }
+}
+
+// cloneURL returns a copy of u so later changes by the caller do not leak
+// into the client.
+func cloneURL(u *url.URL) *url.URL {
+	v := *u
+	return &v
+}
+
+// NewClient returns a new twstock API client configured by opts.
+func NewClient(opts ...ClientOption) *Client {
 	httpClient := &http.Client{}
 	twseBaseURL, _ := url.Parse(defaultTwseBaseURL)
 	tpexBaseURL, _ := url.Parse(defaultTpexBaseURL)
@@ -83,15 +205,36 @@ func NewClient() *Client {
 
 		isinTwseBaseURL: isinTwseBaseURL,
 		isinTwseDecoder: traditionalchinese.Big5.NewDecoder(),
+
+		limiters:          map[Host]*rateLimiter{},
+		retryPolicy:       DefaultRetryPolicy,
+		realtimeBatchSize: defaultRealtimeBatchSize,
+	}
+	for host, limit := range defaultRateLimits {
+		c.limiters[host] = newRateLimiter(limit.requests, limit.per)
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	c.MarketData = &MarketDataService{client: c}
 	c.Security = &SecurityService{client: c}
 	c.Quote = &QuoteService{client: c}
+	c.Valuation = &ValuationService{client: c}
+	c.Institutional = &InstitutionalService{client: c}
+	c.Margin = &MarginService{client: c}
+	c.SBL = &SBLService{client: c}
+	c.ForeignHolding = &ForeignHoldingService{client: c}
+	c.DayTrading = &DayTradingService{client: c}
 	return c
 }
 
 // NewRequest creates an API request.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, urlStr, body)
+}
+
+// NewRequestWithContext creates an API request with the provided context.
+func (c *Client) NewRequestWithContext(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	var buf io.Reader
 	contentType := ""
 
@@ -100,7 +243,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		contentType = "application/x-www-form-urlencoded"
 	}
 
-	req, err := http.NewRequest(method, urlStr, buf)
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, buf)
 	if err != nil {
 		return nil, err
 	}
@@ -108,40 +251,46 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	return req, nil
 }
 
 // Do sends an API request and returns the API response.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req) //nolint:gosec
-	if err != nil {
-		return nil, err
+	resp, err := c.send(req)
+	if resp != nil {
+		defer resp.Body.Close()
 	}
-
-	defer resp.Body.Close()
-
-	err = CheckResponse(resp)
 	if err != nil {
 		return resp, err
 	}
 
 	if v != nil {
-		err = json.NewDecoder(resp.Body).Decode(v)
+		body := bufio.NewReader(resp.Body)
+		// 被封鎖時會回傳 HTML 頁面而不是 JSON
+		if isHTML(body) {
+			return resp, &RateLimitError{Response: resp, RetryAfter: defaultBanWait}
+		}
+		err = json.NewDecoder(body).Decode(v)
 	}
 	return resp, err
 }
 
+// DoWithContext sends an API request with the provided context and returns
+// the API response.
+func (c *Client) DoWithContext(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx), v)
+}
+
 // DoTransformToDocument sends an API request and returns the goquery.Document.
 func (c *Client) DoTransformToDocument(req *http.Request, t transform.Transformer) (*goquery.Document, error) {
-	resp, err := c.client.Do(req) //nolint:gosec
-	if err != nil {
-		return nil, err
+	resp, err := c.send(req)
+	if resp != nil {
+		defer resp.Body.Close()
 	}
-
-	defer resp.Body.Close()
-
-	err = CheckResponse(resp)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +303,21 @@ func (c *Client) DoTransformToDocument(req *http.Request, t transform.Transforme
 	return doc, nil
 }
 
+// DoTransformToDocumentWithContext sends an API request with the provided
+// context and returns the goquery.Document.
+func (c *Client) DoTransformToDocumentWithContext(ctx context.Context, req *http.Request, t transform.Transformer) (*goquery.Document, error) {
+	return c.DoTransformToDocument(req.WithContext(ctx), t)
+}
+
+// contextError returns the context's error if the request was canceled or
+// timed out, since that is more useful than the transport error wrapping it.
+func contextError(req *http.Request, err error) error {
+	if ctxErr := req.Context().Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 }
@@ -164,6 +328,69 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode)
 }
 
+// StatError occurs when the API responds with a stat other than OK. It
+// keeps the raw message so callers can classify the failure with the Is*
+// methods or errors.Is instead of matching the Chinese text.
+type StatError struct {
+	Endpoint string     // 請求的 API 路徑
+	Params   url.Values // 請求參數
+	Stat     string     // 網站回傳的 stat 或 rtmessage
+}
+
+func newStatError(u *url.URL, stat string) *StatError {
+	return &StatError{Endpoint: u.Path, Params: u.Query(), Stat: stat}
+}
+
+func (e *StatError) Error() string {
+	if len(e.Params) == 0 {
+		return fmt.Sprintf("%s: invalid state: %s", e.Endpoint, e.Stat)
+	}
+	return fmt.Sprintf("%s?%s: invalid state: %s", e.Endpoint, e.Params.Encode(), e.Stat)
+}
+
+// Is reports whether the stat means ErrNoData or ErrDateOutOffRange.
+func (e *StatError) Is(target error) bool {
+	switch target {
+	case ErrNoData:
+		return e.isNoData()
+	case ErrDateOutOffRange:
+		return e.IsFutureDate() || strings.HasPrefix(e.Stat, "查詢日期小於")
+	}
+	return false
+}
+
+func (e *StatError) isNoData() bool {
+	return strings.Contains(e.Stat, "沒有符合條件的資料")
+}
+
+// hasCode reports whether the request queried a single security.
+func (e *StatError) hasCode() bool {
+	return e.Params.Get("stockNo") != "" || e.Params.Get("code") != ""
+}
+
+// IsUnknownCode reports whether the queried security code does not exist or
+// has no data.
+func (e *StatError) IsUnknownCode() bool {
+	return strings.Contains(e.Stat, "查無") || (e.isNoData() && e.hasCode())
+}
+
+// IsHoliday reports whether the queried date is not a trading day.
+func (e *StatError) IsHoliday() bool {
+	return strings.Contains(e.Stat, "休市") ||
+		strings.Contains(e.Stat, "非交易日") ||
+		(e.isNoData() && !e.hasCode())
+}
+
+// IsFutureDate reports whether the queried date is after today.
+func (e *StatError) IsFutureDate() bool {
+	return strings.Contains(e.Stat, "大於今日")
+}
+
+// IsMaintenance reports whether the website is under maintenance.
+func (e *StatError) IsMaintenance() bool {
+	return strings.Contains(e.Stat, "維護")
+}
+
 // CheckResponse checks the API response for errors
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
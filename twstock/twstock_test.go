@@ -1,11 +1,14 @@
 package twstock
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/text/transform"
 )
@@ -22,12 +25,18 @@ func setup() (client *Client, mux *http.ServeMux, teardown func()) {
 
 	// client is the fugle client being tested and is
 	// configured to use test server.
-	client = NewClient()
 	url, _ := url.Parse(server.URL + "/")
-	client.twseBaseURL = url
-	client.tpexBaseURL = url
-	client.misTwseBaseURL = url
-	client.isinTwseBaseURL = url
+	client = NewClient(
+		WithTwseBaseURL(url),
+		WithTpexBaseURL(url),
+		WithMisBaseURL(url),
+		WithIsinBaseURL(url),
+		WithRateLimit(TwseHost, 0, 0),
+		WithRateLimit(TpexHost, 0, 0),
+		WithRateLimit(MisHost, 0, 0),
+		WithRateLimit(IsinHost, 0, 0),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	)
 
 	return client, mux, server.Close
 }
@@ -66,6 +75,77 @@ func TestAddOptions_QueryValues(t *testing.T) {
 	}
 }
 
+func TestNewClient_Options(t *testing.T) {
+	httpClient := &http.Client{}
+	u, _ := url.Parse("http://localhost:8080/")
+	c := NewClient(
+		WithHTTPClient(httpClient),
+		WithTwseBaseURL(u),
+		WithTpexBaseURL(u),
+		WithMisBaseURL(u),
+		WithIsinBaseURL(u),
+		WithUserAgent("twstock-test"),
+		WithTwseDecoder(transform.Nop),
+		WithTpexDecoder(transform.Nop),
+		WithIsinDecoder(transform.Nop),
+		WithRealtimeBatchSize(10),
+	)
+	if c.client != httpClient {
+		t.Errorf("NewClient client = %v, want %v", c.client, httpClient)
+	}
+	for _, got := range []*url.URL{c.twseBaseURL, c.tpexBaseURL, c.misTwseBaseURL, c.isinTwseBaseURL} {
+		if got.String() != u.String() {
+			t.Errorf("NewClient base URL = %v, want %v", got, u)
+		}
+		if got == u {
+			t.Error("NewClient base URL shares the caller's url.URL")
+		}
+	}
+	if c.isinTwseDecoder != transform.Nop {
+		t.Errorf("NewClient isinTwseDecoder = %v, want %v", c.isinTwseDecoder, transform.Nop)
+	}
+
+	if c.realtimeBatchSize != 10 {
+		t.Errorf("NewClient realtimeBatchSize = %v, want %v", c.realtimeBatchSize, 10)
+	}
+
+	req, err := c.NewRequest("GET", ".", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if got := req.Header.Get("User-Agent"); got != "twstock-test" {
+		t.Errorf("NewRequest User-Agent = %v, want %v", got, "twstock-test")
+	}
+
+	c = NewClient(WithHTTPClient(nil))
+	if c.client == nil {
+		t.Error("NewClient client = nil, want default client")
+	}
+
+	c = NewClient(WithTwseBaseURL(nil), WithTpexBaseURL(nil), WithMisBaseURL(nil), WithIsinBaseURL(nil))
+	for got, want := range map[*url.URL]string{
+		c.twseBaseURL:     defaultTwseBaseURL,
+		c.tpexBaseURL:     defaultTpexBaseURL,
+		c.misTwseBaseURL:  defaultMisTwseBaseURL,
+		c.isinTwseBaseURL: defaultIsinTwseBaseURL,
+	} {
+		if got == nil || got.String() != want {
+			t.Errorf("NewClient base URL = %v, want %v", got, want)
+		}
+	}
+
+	c = NewClient(WithTwseDecoder(nil), WithTpexDecoder(nil), WithIsinDecoder(nil))
+	if c.twseDecoder == nil {
+		t.Error("NewClient twseDecoder = nil, want default decoder")
+	}
+	if c.tpexDecoder == nil {
+		t.Error("NewClient tpexDecoder = nil, want default decoder")
+	}
+	if c.isinTwseDecoder == nil {
+		t.Error("NewClient isinTwseDecoder = nil, want default decoder")
+	}
+}
+
 func TestNewRequest_BadURL(t *testing.T) {
 	c := NewClient()
 	_, err := c.NewRequest("GET", ":", nil)
@@ -79,6 +159,41 @@ func TestNewRequest_BadMethod(t *testing.T) {
 	}
 }
 
+func TestNewRequestWithContext_NilContext(t *testing.T) {
+	c := NewClient()
+	//nolint:staticcheck
+	if _, err := c.NewRequestWithContext(nil, "GET", ".", nil); err == nil {
+		t.Fatal("NewRequestWithContext returned nil; expected error")
+	}
+}
+
+func TestDoWithContext_Canceled(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/test-url", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("client.DoWithContext sent a request with a canceled context")
+	})
+
+	u, _ := client.twseBaseURL.Parse("/test-url")
+	req, err := client.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		t.Fatalf("client.NewRequest returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.DoWithContext(ctx, req, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("client.DoWithContext returned %v, want %v", err, context.Canceled)
+	}
+
+	_, err = client.DoTransformToDocumentWithContext(ctx, req, transform.Nop)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("client.DoTransformToDocumentWithContext returned %v, want %v", err, context.Canceled)
+	}
+}
+
 func TestDo_BadRequestURL(t *testing.T) {
 	client, _, teardown := setup()
 	defer teardown()
@@ -148,3 +263,71 @@ func TestDoTransformToDocument_BadDocument(t *testing.T) {
 		t.Error("client.DoTransformToDocument err = nil, want error")
 	}
 }
+
+func TestStatError(t *testing.T) {
+	var testCases = map[string]struct {
+		err                                      *StatError
+		unknownCode, holiday, future, maintained bool
+		noData, outOfRange                       bool
+	}{
+		"unknown code": {
+			err:         &StatError{Endpoint: twseQuotesPath, Params: url.Values{"stockNo": {"0000"}}, Stat: "很抱歉，沒有符合條件的資料!"},
+			unknownCode: true,
+			noData:      true,
+		},
+		"holiday": {
+			err:     &StatError{Endpoint: twseMarketDataPath, Params: url.Values{"date": {"20220101"}}, Stat: "很抱歉，沒有符合條件的資料!"},
+			holiday: true,
+			noData:  true,
+		},
+		"future date": {
+			err:        &StatError{Endpoint: twseQuotesPath, Stat: "查詢日期大於今日，請重新查詢!"},
+			future:     true,
+			outOfRange: true,
+		},
+		"before minimum": {
+			err:        &StatError{Endpoint: twseQuotesPath, Stat: "查詢日期小於99年1月4日，請重新查詢!"},
+			outOfRange: true,
+		},
+		"maintenance": {
+			err:        &StatError{Endpoint: twseQuotesPath, Stat: "系統維護中，請稍後再試"},
+			maintained: true,
+		},
+	}
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := test.err.IsUnknownCode(); got != test.unknownCode {
+				t.Errorf("StatError.IsUnknownCode = %v, want %v", got, test.unknownCode)
+			}
+			if got := test.err.IsHoliday(); got != test.holiday {
+				t.Errorf("StatError.IsHoliday = %v, want %v", got, test.holiday)
+			}
+			if got := test.err.IsFutureDate(); got != test.future {
+				t.Errorf("StatError.IsFutureDate = %v, want %v", got, test.future)
+			}
+			if got := test.err.IsMaintenance(); got != test.maintained {
+				t.Errorf("StatError.IsMaintenance = %v, want %v", got, test.maintained)
+			}
+			if got := errors.Is(test.err, ErrNoData); got != test.noData {
+				t.Errorf("errors.Is(StatError, ErrNoData) = %v, want %v", got, test.noData)
+			}
+			if got := errors.Is(test.err, ErrDateOutOffRange); got != test.outOfRange {
+				t.Errorf("errors.Is(StatError, ErrDateOutOffRange) = %v, want %v", got, test.outOfRange)
+			}
+		})
+	}
+}
+
+func TestStatError_Error(t *testing.T) {
+	err := &StatError{Endpoint: twseQuotesPath, Params: url.Values{"stockNo": {"2330"}}, Stat: "FAIL"}
+	want := twseQuotesPath + "?stockNo=2330: invalid state: FAIL"
+	if got := err.Error(); got != want {
+		t.Errorf("StatError.Error = %v, want %v", got, want)
+	}
+
+	err = &StatError{Endpoint: realtimeQuotesPath, Stat: "FAIL"}
+	want = realtimeQuotesPath + ": invalid state: FAIL"
+	if got := err.Error(); got != want {
+		t.Errorf("StatError.Error = %v, want %v", got, want)
+	}
+}
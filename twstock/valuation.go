@@ -0,0 +1,305 @@
+package twstock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/shopspring/decimal"
+)
+
+type ValuationService struct {
+	client *Client
+}
+
+const (
+	// 上市個股日本益比、殖利率及股價淨值比（依代碼查詢）
+	twseValuationsPath = "/rwd/zh/afterTrading/BWIBBU"
+
+	// 上市個股日本益比、殖利率及股價淨值比（依日期查詢）
+	twseDailyValuationsPath = "/rwd/zh/afterTrading/BWIBBU_d"
+
+	// 上櫃個股本益比、殖利率及股價淨值比（依代碼查詢）
+	tpexValuationsPath = "/www/zh-tw/afterTrading/peQryStock"
+
+	// 上櫃個股本益比、殖利率及股價淨值比（依日期查詢）
+	tpexDailyValuationsPath = "/www/zh-tw/afterTrading/peQryDate"
+)
+
+// 個股本益比、殖利率及股價淨值比
+type Valuation struct {
+	Date          civil.Date      // 日期
+	Code          string          // 股票代號
+	PERatio       decimal.Decimal // 本益比，虧損等無法計算時為 0
+	DividendYield decimal.Decimal // 殖利率（%）
+	PBRatio       decimal.Decimal // 股價淨值比
+	DividendYear  int             // 股利年度（西元年），沒有股利時為 0
+}
+
+type twseDailyValuationOptions struct {
+	Response   string `url:"response"`
+	Date       string `url:"date"`
+	SelectType string `url:"selectType"`
+}
+
+// 無法計算本益比等數值時網站會回傳 - 或 N/A
+func parseRatio(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "N/A" || strings.Trim(s, "-") == "" {
+		return decimal.Zero, nil
+	}
+	return parsePrice(s)
+}
+
+// 股利年度為民國年，需要轉成西元年
+func parseDividendYear(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.Trim(s, "-") == "" {
+		return 0, nil
+	}
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return year + 1911, nil
+}
+
+// valuationFields are the headers used by every valuation report.
+var valuationFields = []string{"本益比", "殖利率(%)", "股價淨值比", "股利年度"}
+
+// parse converts a row to Valuation, indexes are the columns of
+// valuationFields in the row.
+func (*ValuationService) parse(date civil.Date, code string, data []string, indexes []int) (Valuation, error) {
+	valuation := Valuation{Date: date, Code: code}
+	for _, i := range indexes {
+		if i >= len(data) {
+			return valuation, fmt.Errorf("failed parsing valuation fields")
+		}
+	}
+	var err error
+	fields := []struct {
+		name  string
+		value *decimal.Decimal
+	}{
+		{"P/E ratio", &valuation.PERatio},
+		{"dividend yield", &valuation.DividendYield},
+		{"P/B ratio", &valuation.PBRatio},
+	}
+	for i, f := range fields {
+		*f.value, err = parseRatio(data[indexes[i]])
+		if err != nil {
+			return valuation, fmt.Errorf("failed parsing valuation %s: %w", f.name, err)
+		}
+	}
+	valuation.DividendYear, err = parseDividendYear(data[indexes[3]])
+	if err != nil {
+		return valuation, fmt.Errorf("failed parsing valuation dividend year: %w", err)
+	}
+	return valuation, nil
+}
+
+// 台灣證卷交易所或是證券櫃檯買賣中心有最小查詢日期的限制
+func (s *ValuationService) MinimumDate(m Market) civil.Date {
+	if m == TWSE {
+		// 台灣證卷交易所個股本益比、殖利率及股價淨值比最早到民國94年9月
+		return civil.Date{Year: 2005, Month: time.September, Day: 1}
+	}
+	// 證券櫃檯買賣中心個股本益比、殖利率及股價淨值比最早到民國96年1月
+	return civil.Date{Year: 2007, Month: time.January, Day: 1}
+}
+
+// 從台灣證卷交易所下載個股每月的本益比、殖利率及股價淨值比
+func (s *ValuationService) DownloadTwse(ctx context.Context, code string, year int, month time.Month) ([]Valuation, error) {
+	date := civil.Date{Year: year, Month: month, Day: 1}
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseValuationsPath)
+	opts := twseOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		Code:     code,
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	indexes, err := fieldIndexes(resp.Fields, append([]string{"日期"}, valuationFields...)...)
+	if err != nil {
+		return nil, err
+	}
+	valuations := []Valuation{}
+	for _, data := range resp.Data {
+		if len(data) <= indexes[0] {
+			return nil, fmt.Errorf("failed parsing valuation fields")
+		}
+		date, err := parseDate(data[indexes[0]])
+		if err != nil {
+			return nil, err
+		}
+		valuation, err := s.parse(date, code, data, indexes[1:])
+		if err != nil {
+			return nil, err
+		}
+		valuations = append(valuations, valuation)
+	}
+	return valuations, nil
+}
+
+// 從證券櫃檯買賣中心下載個股每月的本益比、殖利率及股價淨值比
+func (s *ValuationService) DownloadTpex(ctx context.Context, code string, year int, month time.Month) ([]Valuation, error) {
+	date := civil.Date{Year: year, Month: month, Day: 1}
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", fmt.Sprintf("%04d-%02d", date.Year, date.Month))
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexValuationsPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+		Code:     code,
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	indexes, err := fieldIndexes(table.Fields, append([]string{"日期"}, valuationFields...)...)
+	if err != nil {
+		return nil, err
+	}
+	valuations := []Valuation{}
+	for _, row := range table.Data {
+		data := tpexStrings(row)
+		if len(data) <= indexes[0] {
+			return nil, fmt.Errorf("failed parsing valuation fields")
+		}
+		date, err := parseDate(data[indexes[0]])
+		if err != nil {
+			return nil, err
+		}
+		valuation, err := s.parse(date, code, data, indexes[1:])
+		if err != nil {
+			return nil, err
+		}
+		valuations = append(valuations, valuation)
+	}
+	return valuations, nil
+}
+
+// 從台灣證卷交易所或證券櫃檯買賣中心下載個股每月的本益比、殖利率及股價淨值比
+func (s *ValuationService) Download(ctx context.Context, code string, year int, month time.Month) ([]Valuation, error) {
+	//nolint:typecheck
+	if security, ok := Securities[code]; ok {
+		switch security.Market {
+		case TWSE:
+			return s.DownloadTwse(ctx, code, year, month)
+		case TPEx:
+			return s.DownloadTpex(ctx, code, year, month)
+		default:
+			return nil, fmt.Errorf("invalid market: %s", security.Market)
+		}
+	}
+	return nil, fmt.Errorf("invalid code: %s", code)
+}
+
+// 從台灣證卷交易所下載指定日期所有上市個股的本益比、殖利率及股價淨值比
+func (s *ValuationService) DownloadTwseDaily(ctx context.Context, date civil.Date) (map[string]Valuation, error) {
+	if date.Before(s.MinimumDate(TWSE)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.twseBaseURL.Parse(twseDailyValuationsPath)
+	opts := twseDailyValuationOptions{
+		Response:   "json",
+		Date:       fmt.Sprintf("%04d%02d%02d", date.Year, date.Month, date.Day),
+		SelectType: "ALL",
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &twseResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Stat != "OK" {
+		return nil, newStatError(url, resp.Stat)
+	}
+	indexes, err := fieldIndexes(resp.Fields, append([]string{"證券代號"}, valuationFields...)...)
+	if err != nil {
+		return nil, err
+	}
+	valuations := map[string]Valuation{}
+	for _, data := range resp.Data {
+		if len(data) <= indexes[0] {
+			return nil, fmt.Errorf("failed parsing valuation fields")
+		}
+		code := strings.TrimSpace(data[indexes[0]])
+		valuation, err := s.parse(date, code, data, indexes[1:])
+		if err != nil {
+			return nil, err
+		}
+		valuations[code] = valuation
+	}
+	return valuations, nil
+}
+
+// 從證券櫃檯買賣中心下載指定日期所有上櫃個股的本益比、殖利率及股價淨值比
+func (s *ValuationService) DownloadTpexDaily(ctx context.Context, date civil.Date) (map[string]Valuation, error) {
+	if date.Before(s.MinimumDate(TPEx)) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	url, _ := s.client.tpexBaseURL.Parse(tpexDailyValuationsPath)
+	opts := tpexOptions{
+		Response: "json",
+		Date:     fmt.Sprintf("%04d/%02d/%02d", date.Year, date.Month, date.Day),
+	}
+	url, _ = addOptions(url, opts)
+	req, _ := s.client.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	resp := &tpexResponse{}
+	_, err := s.client.Do(req, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if !isTpexStatOK(resp.Stat) {
+		return nil, newStatError(url, resp.Stat)
+	}
+	if len(resp.Tables) == 0 || resp.Tables[0].TotalCount == 0 {
+		return nil, ErrNoData
+	}
+	table := resp.Tables[0]
+	indexes, err := fieldIndexes(table.Fields, append([]string{"股票代號"}, valuationFields...)...)
+	if err != nil {
+		return nil, err
+	}
+	valuations := map[string]Valuation{}
+	for _, row := range table.Data {
+		data := tpexStrings(row)
+		if len(data) <= indexes[0] {
+			return nil, fmt.Errorf("failed parsing valuation fields")
+		}
+		code := strings.TrimSpace(data[indexes[0]])
+		valuation, err := s.parse(date, code, data, indexes[1:])
+		if err != nil {
+			return nil, err
+		}
+		valuations[code] = valuation
+	}
+	return valuations, nil
+}
@@ -0,0 +1,606 @@
+package twstock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-sql/civil"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestValuationService_DownloadTwse(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("stockNo"), "2330"; got != want {
+			t.Errorf("Request stockNo = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("date"), "20220801"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220801",
+			"title": "111年08月 2330 台積電 個股日本益比、殖利率及股價淨值比",
+			"fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比", "財報年/季"],
+			"data": [
+				["111年08月01日", "2.03", "110", "14.91", "4.88", "111/1"],
+				["111年08月02日", "2.08", "110", "14.59", "4.78", "111/1"]
+			]
+		}`)
+	})
+
+	valuations, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err != nil {
+		t.Fatalf("Valuation.DownloadTwse returned error: %v", err)
+	}
+	want := []Valuation{
+		{
+			Date:          civil.Date{Year: 2022, Month: time.August, Day: 1},
+			Code:          "2330",
+			PERatio:       decimal.RequireFromString("14.91"),
+			DividendYield: decimal.RequireFromString("2.03"),
+			PBRatio:       decimal.RequireFromString("4.88"),
+			DividendYear:  2021,
+		},
+		{
+			Date:          civil.Date{Year: 2022, Month: time.August, Day: 2},
+			Code:          "2330",
+			PERatio:       decimal.RequireFromString("14.59"),
+			DividendYield: decimal.RequireFromString("2.08"),
+			PBRatio:       decimal.RequireFromString("4.78"),
+			DividendYear:  2021,
+		},
+	}
+	if !cmp.Equal(valuations, want) {
+		t.Errorf("Valuation.DownloadTwse returned %+v, want %+v", valuations, want)
+	}
+}
+
+func TestValuationService_DownloadTwseErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Valuation.DownloadTwse returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestValuationService_DownloadTwseBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)"], "data": []}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 日期,殖利率(%)")
+}
+
+func TestValuationService_DownloadTwseBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [["111年08月01日"]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation fields")
+}
+
+func TestValuationService_DownloadTwseBadDate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [["BAD", "2.03", "110", "14.91", "4.88"]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote date")
+}
+
+func TestValuationService_DownloadTwseBadDividendYield(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [["111年08月01日", "BAD", "110", "14.91", "4.88"]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation dividend yield")
+}
+
+func TestValuationService_DownloadTwseBadDividendYear(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [["111年08月01日", "2.03", "BAD", "14.91", "4.88"]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation dividend year")
+}
+
+func TestValuationService_DownloadTwseInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Valuation.DownloadTwse(context.Background(), "2330", 2005, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwse returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2005-08")
+}
+
+func TestValuationService_DownloadTpex(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("code"), "3374"; got != want {
+			t.Errorf("Request code = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("date"), "2022/08/01"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"fields": ["日期", "本益比", "每股股利", "股利年度", "殖利率(%)", "股價淨值比"],
+					"data": [
+						["111/08/01", "22.47", "2.3", "110", "1.74", "3.12"],
+						["111/08/02", "N/A", "0", "", "0.00", "3.01"]
+					],
+					"totalCount": 2
+				}
+			]
+		}`)
+	})
+
+	valuations, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if err != nil {
+		t.Fatalf("Valuation.DownloadTpex returned error: %v", err)
+	}
+	want := []Valuation{
+		{
+			Date:          civil.Date{Year: 2022, Month: time.August, Day: 1},
+			Code:          "3374",
+			PERatio:       decimal.RequireFromString("22.47"),
+			DividendYield: decimal.RequireFromString("1.74"),
+			PBRatio:       decimal.RequireFromString("3.12"),
+			DividendYear:  2021,
+		},
+		{
+			Date:          civil.Date{Year: 2022, Month: time.August, Day: 2},
+			Code:          "3374",
+			PERatio:       decimal.Zero,
+			DividendYield: decimal.Zero,
+			PBRatio:       decimal.RequireFromString("3.01"),
+		},
+	}
+	if !cmp.Equal(valuations, want) {
+		t.Errorf("Valuation.DownloadTpex returned %+v, want %+v", valuations, want)
+	}
+}
+
+func TestValuationService_DownloadTpexBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("Valuation.DownloadTpex returned %v, want *StatError", err)
+	}
+}
+
+func TestValuationService_DownloadTpexErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": []}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Valuation.DownloadTpex returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestValuationService_DownloadTpexBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["日期"], "data": [["111/08/01"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 日期")
+}
+
+func TestValuationService_DownloadTpexBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["日期", "本益比", "股利年度", "殖利率(%)", "股價淨值比"], "data": [[]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation fields")
+}
+
+func TestValuationService_DownloadTpexBadDate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["日期", "本益比", "股利年度", "殖利率(%)", "股價淨值比"], "data": [["BAD", "1", "110", "1", "1"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing quote date")
+}
+
+func TestValuationService_DownloadTpexBadPBRatio(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["日期", "本益比", "股利年度", "殖利率(%)", "股價淨值比"], "data": [["111/08/01", "1", "110", "1", "BAD"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2022, time.August)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation P/B ratio")
+}
+
+func TestValuationService_DownloadTpexInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Valuation.DownloadTpex(context.Background(), "3374", 2006, time.December)
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpex returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2006-12")
+}
+
+func TestValuationService_Download(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["日期", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": []}`)
+	})
+	mux.HandleFunc(tpexValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.Valuation.Download(context.Background(), "2330", 2022, time.August)
+	if err != nil {
+		t.Errorf("Valuation.Download returned error: %v", err)
+	}
+	_, err = client.Valuation.Download(context.Background(), "3374", 2022, time.August)
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Valuation.Download returned %v, want %v", err, ErrNoData)
+	}
+	_, err = client.Valuation.Download(context.Background(), "BAD", 2022, time.August)
+	if err == nil {
+		t.Error("Valuation.Download returned nil; expected error")
+	}
+}
+
+func TestValuationService_DownloadTwseDaily(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "20220822"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		if got, want := r.URL.Query().Get("selectType"), "ALL"; got != want {
+			t.Errorf("Request selectType = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "OK",
+			"date": "20220822",
+			"fields": ["證券代號", "證券名稱", "收盤價", "殖利率(%)", "股利年度", "本益比", "股價淨值比", "財報年/季"],
+			"data": [
+				["2330", "台積電", "510.00", "2.16", "110", "14.23", "4.66", "111/2"],
+				["1258", "其祥-KY", "35.10", "0.00", "-", "-", "1.09", "111/2"]
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	valuations, err := client.Valuation.DownloadTwseDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Valuation.DownloadTwseDaily returned error: %v", err)
+	}
+	want := map[string]Valuation{
+		"2330": {
+			Date:          date,
+			Code:          "2330",
+			PERatio:       decimal.RequireFromString("14.23"),
+			DividendYield: decimal.RequireFromString("2.16"),
+			PBRatio:       decimal.RequireFromString("4.66"),
+			DividendYear:  2021,
+		},
+		"1258": {
+			Date:          date,
+			Code:          "1258",
+			PERatio:       decimal.Zero,
+			DividendYield: decimal.Zero,
+			PBRatio:       decimal.RequireFromString("1.09"),
+		},
+	}
+	if !cmp.Equal(valuations, want) {
+		t.Errorf("Valuation.DownloadTwseDaily returned %+v, want %+v", valuations, want)
+	}
+}
+
+func TestValuationService_DownloadTwseDailyErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "很抱歉，沒有符合條件的資料!"}`)
+	})
+
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Valuation.DownloadTwseDaily returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestValuationService_DownloadTwseDailyBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["證券代號"], "data": []}`)
+	})
+
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 證券代號")
+}
+
+func TestValuationService_DownloadTwseDailyBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["證券代號", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [[]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation fields")
+}
+
+func TestValuationService_DownloadTwseDailyBadPERatio(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(twseDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "OK", "fields": ["證券代號", "殖利率(%)", "股利年度", "本益比", "股價淨值比"], "data": [["2330", "1", "110", "BAD", "1"]]}`)
+	})
+
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation P/E ratio")
+}
+
+func TestValuationService_DownloadTwseDailyInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Valuation.DownloadTwseDaily(context.Background(), civil.Date{Year: 2005, Month: time.August, Day: 31})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTwseDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2005-08-31")
+}
+
+func TestValuationService_DownloadTpexDaily(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("date"), "2022/08/22"; got != want {
+			t.Errorf("Request date = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `
+		{
+			"stat": "ok",
+			"tables": [
+				{
+					"fields": ["股票代號", "名稱", "本益比", "每股股利", "股利年度", "殖利率(%)", "股價淨值比", "財報年/季"],
+					"data": [["3374", "精材", "21.39", 2.3, "110", "1.79", "2.97", "111/2"]],
+					"totalCount": 1
+				}
+			]
+		}`)
+	})
+
+	date := civil.Date{Year: 2022, Month: time.August, Day: 22}
+	valuations, err := client.Valuation.DownloadTpexDaily(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Valuation.DownloadTpexDaily returned error: %v", err)
+	}
+	want := map[string]Valuation{
+		"3374": {
+			Date:          date,
+			Code:          "3374",
+			PERatio:       decimal.RequireFromString("21.39"),
+			DividendYield: decimal.RequireFromString("1.79"),
+			PBRatio:       decimal.RequireFromString("2.97"),
+			DividendYear:  2021,
+		},
+	}
+	if !cmp.Equal(valuations, want) {
+		t.Errorf("Valuation.DownloadTpexDaily returned %+v, want %+v", valuations, want)
+	}
+}
+
+func TestValuationService_DownloadTpexDailyBadStat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "error"}`)
+	})
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	var statErr *StatError
+	if !errors.As(err, &statErr) {
+		t.Errorf("Valuation.DownloadTpexDaily returned %v, want *StatError", err)
+	}
+}
+
+func TestValuationService_DownloadTpexDailyErrNoData(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"totalCount": 0}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if !errors.Is(err, ErrNoData) {
+		t.Errorf("Valuation.DownloadTpexDaily returned %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestValuationService_DownloadTpexDailyBadFields(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["股票代號"], "data": [["3374"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing fields: 股票代號")
+}
+
+func TestValuationService_DownloadTpexDailyBadDataLength(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["股票代號", "本益比", "股利年度", "殖利率(%)", "股價淨值比"], "data": [[]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation fields")
+}
+
+func TestValuationService_DownloadTpexDailyShortRow(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tpexDailyValuationsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "tables": [{"fields": ["股票代號", "本益比", "股利年度", "殖利率(%)", "股價淨值比"], "data": [["3374", "1", "110", "1"]], "totalCount": 1}]}`)
+	})
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2022, Month: time.August, Day: 22})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "failed parsing valuation fields")
+}
+
+func TestValuationService_DownloadTpexDailyInvalidDate(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Valuation.DownloadTpexDaily(context.Background(), civil.Date{Year: 2006, Month: time.December, Day: 29})
+	if err == nil {
+		t.Fatal("Valuation.DownloadTpexDaily returned nil; expected error")
+	}
+	testErrorContains(t, err, "invalid date: 2006-12-29")
+}
+
+func TestFieldIndexes(t *testing.T) {
+	indexes, err := fieldIndexes([]string{"代號", " 收盤 ", "漲跌"}, "漲跌", "收盤")
+	if err != nil {
+		t.Fatalf("fieldIndexes returned error: %v", err)
+	}
+	if want := []int{2, 1}; !cmp.Equal(indexes, want) {
+		t.Errorf("fieldIndexes returned %v, want %v", indexes, want)
+	}
+	if _, err := fieldIndexes([]string{"代號"}, "收盤"); err == nil {
+		t.Error("fieldIndexes returned nil; expected error")
+	}
+}